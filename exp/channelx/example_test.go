@@ -280,3 +280,55 @@ func ExamplePuller_WithPanicHandler() {
 	// panicked on item 9
 	// [0 1 2 3 4 5 6 7 8 0]
 }
+
+func ExamplePuller_WithHandlerContext() {
+	// Note that itemChan is buffered.
+	itemChan := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		itemChan <- i
+	}
+
+	handledItems := make([]int, 0)
+	handlerFunc := func(ctx context.Context, item int) error {
+		if item%2 == 1 {
+			return fmt.Errorf("odd item %d", item)
+		}
+		if item == 6 {
+			// Stop pulling items once item 6 is pulled.
+			return channelx.ErrStopPulling
+		}
+
+		handledItems = append(handledItems, item)
+
+		return nil
+	}
+
+	errorHandlerFunc := func(item int, err error) {
+		fmt.Println(err)
+	}
+
+	// Create a puller to pull items from itemChan and assign handlerFunc to handle the items.
+	puller := channelx.NewPuller[int]().
+		WithNotifyChannel(itemChan).
+		WithHandlerContext(handlerFunc).
+		// Assign errorHandlerFunc to handle the errors returned by handlerFunc.
+		WithErrorHandler(errorHandlerFunc).
+		// Handle the items buffered in itemChan before stopping.
+		WithDrainOnStop().
+		StartPull(context.Background())
+
+	// StopPull blocks until the buffered items are handled.
+	err := puller.StopPull(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Let's print out the handled items.
+	fmt.Println(handledItems)
+
+	// Output:
+	// odd item 1
+	// odd item 3
+	// odd item 5
+	// [0 2 4]
+}
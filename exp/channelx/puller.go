@@ -2,28 +2,145 @@ package channelx
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/nekomeowww/fo"
 	"github.com/sourcegraph/conc/panics"
 	"github.com/sourcegraph/conc/pool"
 )
 
-// Puller is a generic long-running puller to pull items from a channel.
+var (
+	// ErrStopPulling can be returned by the handlers to signal the puller to stop pulling items.
+	ErrStopPulling = errors.New("channelx: stop pulling")
+	// ErrSourcesClosed is reported by Err when the puller stops because all of the notify
+	// channels and ticker channels are closed.
+	ErrSourcesClosed = errors.New("channelx: all sources of the puller are closed")
+)
+
+// PullerState is the lifecycle state of the Puller.
+type PullerState int
+
+const (
+	// PullerStateIdle means the puller has not been started yet.
+	PullerStateIdle PullerState = iota
+	// PullerStateRunning means the puller is pulling items.
+	PullerStateRunning
+	// PullerStateStopping means the puller has stopped pulling items and is waiting
+	// for the in-flight handlers to finish.
+	PullerStateStopping
+	// PullerStateStopped means the puller has stopped, it can be started again by StartPull.
+	PullerStateStopped
+)
+
+func (s PullerState) String() string {
+	switch s {
+	case PullerStateIdle:
+		return "idle"
+	case PullerStateRunning:
+		return "running"
+	case PullerStateStopping:
+		return "stopping"
+	case PullerStateStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// AbandonedItemsError is returned by StopPull when the context passed to StopPull is done
+// before the in-flight handlers finish, Items holds the items that were being handled.
+type AbandonedItemsError[T any] struct {
+	Items []T
+	Err   error
+}
+
+func (e *AbandonedItemsError[T]) Error() string {
+	return fmt.Sprintf("channelx: abandoned %d in-flight items when stopping the puller: %v: %v", len(e.Items), e.Err, e.Items)
+}
+
+func (e *AbandonedItemsError[T]) Unwrap() error {
+	return e.Err
+}
+
+// Puller is a generic long-running puller to pull items from channels and tickers.
 type Puller[T any] struct {
-	notifyChan <-chan T
-	tickerChan <-chan time.Time
-	ticker     *time.Ticker
+	sources []*pullerSource[T]
 
-	updateFromFunc             func(time.Time) T
-	updateHandlerFunc          func(item T) (shouldContinue, shouldReturn bool)
+	updateHandlerFunc          func(ctx context.Context, item T) error
 	updateHandleAsynchronously bool
-	updateHandlePool           *pool.Pool
+	updateHandleMaxGoroutine   int
 	panicHandlerFunc           func(panicValue *panics.Recovered)
+	errorHandlerFunc           func(item T, err error)
+	retryPolicy                *RetryPolicy
+	handlerTimeout             time.Duration
+	deadLetterHandlerFunc      func(letter DeadLetter[T])
+	deadLetterChan             chan<- DeadLetter[T]
+	drainOnStop                bool
+	instrumentation            *pullerInstrumentation
+	spanParentExtractorFunc    func(ctx context.Context, item T) context.Context
+	keyFunc                    func(item T) (any, error)
+	batchHandlerFunc           func(items []T)
+	batchSize                  int
+	batchMaxWait               time.Duration
+	debounceWait               time.Duration
+	debounceMergeFunc          func(pending, item T) T
+
+	mutex   sync.Mutex
+	state   PullerState
+	err     error
+	done    chan struct{}
+	current *pullerRun[T]
+}
+
+// pullerRun holds the states of a single run of the puller, from StartPull to stopped.
+type pullerRun[T any] struct {
+	// cancel cancels the ctx passed to the handlers.
+	cancel context.CancelFunc
+	// pullCancel stops pulling items from the sources.
+	pullCancel context.CancelFunc
+	// stopping is closed once the puller stops pulling items for any reason.
+	stopping      <-chan struct{}
+	stopRequested atomic.Bool
+	stopMutex     sync.Mutex
+	stopped       bool
+	stopErr       error
+
+	handlePool  *pool.Pool
+	keyedQueues *keyedQueues
+	debouncer   *debouncer[T]
+	batcher     *batcher[T]
+	inFlight    *inFlightItems[T]
+	finishOnce  sync.Once
+	done        chan struct{}
+
+	// syncMutex serializes the synchronous handlings, since the debounced items and the
+	// batches are handled by the timers as well.
+	syncMutex sync.Mutex
+}
+
+// stop stops pulling items for the error returned by the handlers, only the first
+// error is kept.
+func (r *pullerRun[T]) stop(err error) {
+	r.stopMutex.Lock()
+	if !r.stopped {
+		r.stopped = true
+		r.stopErr = err
+	}
+	r.stopMutex.Unlock()
 
-	alreadyStarted    bool
-	alreadyClosed     bool
-	contextCancelFunc context.CancelFunc
+	r.pullCancel()
+}
+
+// stopReason returns the error passed to stop.
+func (r *pullerRun[T]) stopReason() error {
+	r.stopMutex.Lock()
+	defer r.stopMutex.Unlock()
+
+	return r.stopErr
 }
 
 // New creates a new long-running puller to pull items.
@@ -31,49 +148,73 @@ func NewPuller[T any]() *Puller[T] {
 	return new(Puller[T])
 }
 
-// WithChannel assigns channel to pull items from.
-func (p *Puller[T]) WithNotifyChannel(updateChan <-chan T) *Puller[T] {
-	p.notifyChan = updateChan
+// WithNotifyChannel adds a channel to pull items from. It can be called several times
+// to pull items from several channels, and to be used together with the tickers.
+func (p *Puller[T]) WithNotifyChannel(updateChan <-chan T, opts ...PullerSourceOption[T]) *Puller[T] {
+	if updateChan == nil {
+		return p
+	}
 
-	return p
-}
+	source := newPullerSource(opts)
+	source.notifyChan = updateChan
 
-// WithTickerChannel assigns channel to pull items from with a ticker.
-func (p *Puller[T]) WithTickerChannel(tickerChan <-chan time.Time, pullFromFunc func(time.Time) T) *Puller[T] {
-	p.tickerChan = tickerChan
-	p.updateFromFunc = pullFromFunc
+	p.sources = append(p.sources, source)
 
 	return p
 }
 
-// WithTickerInterval assigns ticker interval to pull items from with a ticker.
-func (p *Puller[T]) WithTickerInterval(interval time.Duration, pullFromFunc func(time.Time) T) *Puller[T] {
-	p.ticker = time.NewTicker(interval)
-	p.tickerChan = p.ticker.C
-	p.updateFromFunc = pullFromFunc
+// WithTickerChannel adds a ticker channel to pull items from, the items are created by
+// pullFromFunc on every tick. The source is removed once tickerChan is closed.
+func (p *Puller[T]) WithTickerChannel(tickerChan <-chan time.Time, pullFromFunc func(time.Time) T, opts ...PullerSourceOption[T]) *Puller[T] {
+	source := newPullerSource(opts)
+	source.tickerChan = tickerChan
+	source.pullFromFunc = pullFromFunc
+
+	p.sources = append(p.sources, source)
 
 	return p
 }
 
+// WithTickerInterval adds a ticker with the interval to pull items from, the items are
+// created by pullFromFunc on every tick. The source can be configured by the same options
+// as WithTickerSchedule. It panics if interval is not positive.
+func (p *Puller[T]) WithTickerInterval(interval time.Duration, pullFromFunc func(time.Time) T, opts ...PullerSourceOption[T]) *Puller[T] {
+	return p.WithTickerSchedule(EverySchedule(interval), func(_ context.Context, tick time.Time) (T, bool, error) {
+		return pullFromFunc(tick), true, nil
+	}, opts...)
+}
+
 // WithHandler assigns handler to handle the items pulled from the channel.
 func (p *Puller[T]) WithHandler(handler func(item T)) *Puller[T] {
-	p.updateHandlerFunc = func(item T) (bool, bool) {
+	p.updateHandlerFunc = func(_ context.Context, item T) error {
 		handler(item)
-		return false, false
+		return nil
 	}
 
 	return p
 }
 
+// WithHandlerContext assigns handler to handle the items pulled from the channel. The ctx
+// passed to the handler is cancelled when the puller stops, see StopPull. The errors returned by the
+// handler are retried by the policy assigned by WithRetryPolicy, and then passed to the
+// error handler assigned by WithErrorHandler. The handler may return ErrStopPulling to
+// signal the puller to stop pulling items.
+func (p *Puller[T]) WithHandlerContext(handler func(ctx context.Context, item T) error) *Puller[T] {
+	p.updateHandlerFunc = handler
+
+	return p
+}
+
 // WithHandlerWithShouldContinue assigns handler to handle the items pulled from the channel but
 // the handler can return a bool to indicate whether the puller should skip the current for loop
 // iteration and continue to move on to the next iteration.
 //
-// NOTICE: If the puller has been set to handle the items asynchronously, therefore the
-// shouldContinue boolean value that the handler returns will be ignored.
+// Deprecated: the puller always moves on to the next item after handling, use WithHandler or
+// WithHandlerContext instead.
 func (p *Puller[T]) WithHandlerWithShouldContinue(handler func(item T) bool) *Puller[T] {
-	p.updateHandlerFunc = func(item T) (bool, bool) {
-		return handler(item), false
+	p.updateHandlerFunc = func(_ context.Context, item T) error {
+		_ = handler(item)
+		return nil
 	}
 
 	return p
@@ -82,24 +223,33 @@ func (p *Puller[T]) WithHandlerWithShouldContinue(handler func(item T) bool) *Pu
 // WithHandlerWithShouldReturn assigns handler to handle the items pulled from the channel but
 // the handler can return a bool to indicate whether the puller should stop pulling items.
 //
-// NOTICE: If the puller has been set to handle the items asynchronously, therefore the
-// shouldReturn boolean value that the handler returns will be ignored.
+// Deprecated: use WithHandlerContext and return ErrStopPulling instead.
 func (p *Puller[T]) WithHandlerWithShouldReturn(handler func(item T) bool) *Puller[T] {
-	p.updateHandlerFunc = func(item T) (bool, bool) {
-		return false, handler(item)
+	p.updateHandlerFunc = func(_ context.Context, item T) error {
+		if handler(item) {
+			return ErrStopPulling
+		}
+
+		return nil
 	}
 
 	return p
 }
 
 // WithHandlerWithShouldContinueAndShouldReturn assigns handler to handle the items pulled from the channel but
-// the handler can return two bool values to indicate whether the puller should stop pulling items and
-// whether the puller should skip the current for loop iteration and continue to move on to the next iteration.
+// the handler can return two bool values to indicate whether the puller should skip the current for loop
+// iteration and continue to move on to the next iteration and whether the puller should stop pulling items.
 //
-// NOTICE: If the puller has been set to handle the items asynchronously, therefore the
-// shouldContinue and shouldReturn boolean values that the handler returns will be ignored.
-func (p *Puller[T]) WithHandlerWithShouldContinueAndShouldReturn(handler func(item T) (shouldBreak, shouldContinue bool)) *Puller[T] {
-	p.updateHandlerFunc = handler
+// Deprecated: use WithHandlerContext and return ErrStopPulling instead.
+func (p *Puller[T]) WithHandlerWithShouldContinueAndShouldReturn(handler func(item T) (shouldContinue, shouldReturn bool)) *Puller[T] {
+	p.updateHandlerFunc = func(_ context.Context, item T) error {
+		_, shouldReturn := handler(item)
+		if shouldReturn {
+			return ErrStopPulling
+		}
+
+		return nil
+	}
 
 	return p
 }
@@ -116,9 +266,21 @@ func (p *Puller[T]) WithHandleAsynchronously() *Puller[T] {
 // that handle the items to prevent the goroutines from consuming too much memory when lots of items are pumped
 // to the channel (or request).
 func (p *Puller[T]) WithHandleAsynchronouslyMaxGoroutine(maxGoroutine int) *Puller[T] {
+	if maxGoroutine < 1 {
+		panic("max goroutines of the puller must be greater than zero")
+	}
+
 	p.WithHandleAsynchronously()
 
-	p.updateHandlePool = pool.New().WithMaxGoroutines(maxGoroutine)
+	p.updateHandleMaxGoroutine = maxGoroutine
+
+	return p
+}
+
+// WithDrainOnStop makes StopPull to handle the items buffered in the notify channels
+// before stopping, the tickers are not drained.
+func (p *Puller[T]) WithDrainOnStop() *Puller[T] {
+	p.drainOnStop = true
 
 	return p
 }
@@ -130,179 +292,419 @@ func (p *Puller[T]) WithPanicHandler(handlerFunc func(panicValue *panics.Recover
 	return p
 }
 
-// StartPull starts pulling items from the channel. You may pass a context to signal the puller to stop pulling
-// items from the channel.
+// WithErrorHandler assigns error handler to handle the errors that the handlers assigned by
+// WithHandlerContext return, after the retries assigned by WithRetryPolicy are exhausted.
+// The panics of the handlers are passed as *panics.ErrRecovered.
+func (p *Puller[T]) WithErrorHandler(handlerFunc func(item T, err error)) *Puller[T] {
+	p.errorHandlerFunc = handlerFunc
+
+	return p
+}
+
+// WithRetryPolicy assigns the policy to handle the items again when the handlers return
+// errors or panic.
+func (p *Puller[T]) WithRetryPolicy(policy RetryPolicy) *Puller[T] {
+	p.retryPolicy = &policy
+
+	return p
+}
+
+// WithHandlerTimeout restricts every attempt to handle an item to timeout, the ctx passed to
+// the handlers is cancelled once timeout elapses. The handlers are expected to respect the
+// ctx, context.DeadlineExceeded returned by the handlers is retried as other errors.
+func (p *Puller[T]) WithHandlerTimeout(timeout time.Duration) *Puller[T] {
+	p.handlerTimeout = timeout
+
+	return p
+}
+
+// WithDeadLetterHandler assigns handler to receive the items that failed to be handled after
+// the retries assigned by WithRetryPolicy are exhausted.
+func (p *Puller[T]) WithDeadLetterHandler(handlerFunc func(letter DeadLetter[T])) *Puller[T] {
+	p.deadLetterHandlerFunc = handlerFunc
+
+	return p
+}
+
+// WithDeadLetterChannel assigns channel to receive the items that failed to be handled after
+// the retries assigned by WithRetryPolicy are exhausted. Sending to the channel blocks the
+// handling until the item is received or the puller stops pulling items. Once the puller
+// stops pulling, the items are sent only if the channel is ready to receive, and dropped
+// otherwise, so that StopPull never waits for the channel.
+func (p *Puller[T]) WithDeadLetterChannel(deadLetterChan chan<- DeadLetter[T]) *Puller[T] {
+	p.deadLetterChan = deadLetterChan
+
+	return p
+}
+
+// StartPull starts pulling items from the sources. You may pass a context to signal the puller to stop pulling
+// items from the sources. Calling StartPull on a running puller has no effect, while a stopped puller starts
+// pulling again from the same sources.
 func (c *Puller[T]) StartPull(ctx context.Context) *Puller[T] {
-	if c.alreadyStarted {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.state == PullerStateRunning || c.state == PullerStateStopping {
 		return c
 	}
+	if c.done == nil || c.state == PullerStateStopped {
+		c.done = make(chan struct{})
+	}
 
-	c.alreadyStarted = true
-	if c.tickerChan != nil {
-		c.contextCancelFunc = runWithTicker(
-			ctx,
-			c.updateHandleAsynchronously,
-			c.updateHandlePool,
-			c.tickerChan,
-			c.updateFromFunc,
-			c.updateHandlerFunc,
-			c.panicHandlerFunc,
-		)
+	c.state = PullerStateRunning
+	c.err = nil
 
-		return c
-	}
-	if c.notifyChan != nil {
-		c.contextCancelFunc = run(
-			ctx,
-			c.updateHandleAsynchronously,
-			c.updateHandlePool,
-			c.notifyChan,
-			c.updateHandlerFunc,
-			c.panicHandlerFunc,
-		)
+	// The handlers run with handleCtx, which outlives pullCtx so that the in-flight
+	// handlers are able to finish after the puller stops pulling items.
+	handleCtx, cancel := context.WithCancel(ctx)
+	pullCtx, pullCancel := context.WithCancel(handleCtx)
 
-		return c
+	run := &pullerRun[T]{
+		cancel:     cancel,
+		pullCancel: pullCancel,
+		stopping:   pullCtx.Done(),
+		inFlight:   newInFlightItems[T](),
+		done:       c.done,
+	}
+	if c.updateHandleMaxGoroutine > 0 {
+		run.handlePool = pool.New().WithMaxGoroutines(c.updateHandleMaxGoroutine)
+	}
+	if c.keyFunc != nil {
+		run.keyedQueues = newKeyedQueues()
 	}
 
-	c.contextCancelFunc = func() {}
+	c.current = run
+
+	go c.run(run, handleCtx, pullCtx)
 
 	return c
 }
 
-// StopPull stops pulling items from the channel. You may pass a context to restrict the deadline or
-// call timeout to the action to stop the puller.
+// StopPull stops pulling items from the sources, and then blocks until the in-flight handlers
+// finish. The items buffered in the notify channels are handled before stopping if the puller
+// is created with WithDrainOnStop. You may pass a context to restrict the deadline to wait,
+// an *AbandonedItemsError listing the unfinished items is returned if ctx is done first.
+//
+// The ctx passed to the handlers are cancelled once StopPull returns. Calling StopPull on a
+// puller which is not running has no effect.
 func (c *Puller[T]) StopPull(ctx context.Context) error {
-	if c.alreadyClosed {
+	c.mutex.Lock()
+	if c.state != PullerStateRunning {
+		c.mutex.Unlock()
+		return nil
+	}
+
+	c.state = PullerStateStopping
+	run := c.current
+	c.mutex.Unlock()
+
+	run.stopRequested.Store(true)
+	run.pullCancel()
+
+	select {
+	case <-run.done:
 		return nil
+	case <-ctx.Done():
+		err := &AbandonedItemsError[T]{Items: run.inFlight.snapshot(), Err: ctx.Err()}
+		c.finish(run, err)
+
+		return err
+	}
+}
+
+// State returns the current lifecycle state of the puller.
+func (c *Puller[T]) State() PullerState {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.state
+}
+
+// Done returns a channel that is closed when the puller stops, either by StopPull, the
+// context passed to StartPull, a handler returning ErrStopPulling, or all of the notify
+// channels and ticker channels being closed. Restarting a stopped puller creates a new channel.
+func (c *Puller[T]) Done() <-chan struct{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.done == nil {
+		c.done = make(chan struct{})
+	}
+
+	return c.done
+}
+
+// Err returns the reason why the puller stopped, it returns nil if the puller is not stopped
+// or is stopped by StopPull. Otherwise, it returns the error of the context passed to StartPull,
+// the error returned by the handler wrapping ErrStopPulling, ErrSourcesClosed, or the
+// *AbandonedItemsError returned by StopPull.
+func (c *Puller[T]) Err() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.err
+}
+
+// finish transits the puller to the stopped state, err is reported by Err.
+func (c *Puller[T]) finish(run *pullerRun[T], err error) {
+	run.finishOnce.Do(func() {
+		run.cancel()
+
+		c.mutex.Lock()
+		if c.current == run {
+			c.state = PullerStateStopped
+			c.err = err
+		}
+		c.mutex.Unlock()
+
+		close(run.done)
+	})
+}
+
+func (c *Puller[T]) run(run *pullerRun[T], handleCtx, pullCtx context.Context) {
+	err := c.pull(run, handleCtx, pullCtx)
+
+	// pullCtx is not cancelled if the sources are closed, cancel it to signal the in-flight
+	// handlers that the puller is stopping.
+	run.pullCancel()
+
+	if err != nil {
+		c.mutex.Lock()
+		if c.current == run && c.state == PullerStateRunning {
+			c.state = PullerStateStopping
+		}
+		c.mutex.Unlock()
 	}
 
-	c.alreadyClosed = true
-	if c.ticker != nil {
-		c.ticker.Stop()
+	run.inFlight.wait()
+
+	if run.handlePool != nil {
+		run.handlePool.Wait()
 	}
-	if c.contextCancelFunc != nil {
-		return fo.Invoke0(ctx, func() error {
-			c.contextCancelFunc()
 
-			return nil
+	c.finish(run, err)
+}
+
+// pull pulls items until the puller stops, it returns the reason of the stop.
+func (c *Puller[T]) pull(run *pullerRun[T], handleCtx, pullCtx context.Context) error {
+	fanIn := newPullerFanIn(pullCtx, c.sources, c.reportPullError)
+
+	if c.debounceWait > 0 {
+		run.debouncer = newDebouncer(c.debounceWait, c.debounceMergeFunc, func(item T, source *pullerSource[T]) {
+			c.route(run, handleCtx, item, source)
 		})
 	}
+	if c.batchHandlerFunc != nil {
+		run.batcher = newBatcher(c.batchSize, c.batchMaxWait, func(items []T) {
+			c.dispatchBatch(run, handleCtx, items)
+		})
+	}
+
+	// the pending items are handled once the puller stops pulling, the batcher is closed
+	// after the debouncer, which may pass the pending items to it.
+	defer func() {
+		run.debouncer.close()
+		run.batcher.close()
+	}()
+
+	if len(c.sources) == 0 {
+		<-pullCtx.Done()
+	}
+
+	for {
+		item, source, ok := fanIn.receive()
+		if !ok {
+			break
+		}
+
+		c.accept(run, handleCtx, item, source)
+	}
+
+	if pullCtx.Err() == nil {
+		return ErrSourcesClosed
+	}
+	if !run.stopRequested.Load() {
+		err := run.stopReason()
+		if err != nil {
+			return err
+		}
+
+		return handleCtx.Err()
+	}
+	if !c.drainOnStop {
+		return nil
+	}
+
+	for handleCtx.Err() == nil && run.stopReason() == nil {
+		item, source, ok := fanIn.tryReceiveBuffered()
+		if !ok {
+			break
+		}
+
+		c.accept(run, handleCtx, item, source)
+	}
 
 	return nil
 }
 
-func runHandle[T any](
-	item T,
-	updateHandleAsynchronously bool,
-	updateHandlePool *pool.Pool,
-	handlerFunc func(item T) (shouldContinue, shouldReturn bool),
-	panicHandlerFunc func(panicValue *panics.Recovered),
-) (bool, bool) {
+// accept passes the item pulled from the source to the debouncer if the puller debounces,
+// or routes it to the handlers otherwise.
+func (c *Puller[T]) accept(run *pullerRun[T], ctx context.Context, item T, source *pullerSource[T]) {
+	c.instrumentation.itemReceived(ctx)
+
+	if run.debouncer != nil {
+		run.debouncer.add(item, source)
+		return
+	}
+
+	c.route(run, ctx, item, source)
+}
+
+// route passes the item to the batcher if the puller handles the items in batches, or
+// dispatches it to the handlers otherwise. The items of the sources with their own handlers
+// are never batched.
+func (c *Puller[T]) route(run *pullerRun[T], ctx context.Context, item T, source *pullerSource[T]) {
+	if run.batcher != nil && source.handlerFunc == nil {
+		run.batcher.add(item)
+		return
+	}
+
+	c.dispatch(run, ctx, item, source)
+}
+
+// dispatch handles the item synchronously or asynchronously.
+func (c *Puller[T]) dispatch(run *pullerRun[T], ctx context.Context, item T, source *pullerSource[T]) {
+	handlerFunc := c.updateHandlerFunc
+	if source.handlerFunc != nil {
+		handlerFunc = source.handlerFunc
+	}
 	if handlerFunc == nil {
-		return false, false
+		return
 	}
 
-	if updateHandleAsynchronously {
-		runInGoroutine := func() {
-			var pc panics.Catcher
+	var key any
 
-			pc.Try(func() {
-				_, _ = handlerFunc(item)
-			})
+	if run.keyedQueues != nil {
+		var err error
 
-			if pc.Recovered() != nil && panicHandlerFunc != nil {
-				panicHandlerFunc(pc.Recovered())
+		key, err = c.keyFunc(item)
+		if err != nil {
+			if c.errorHandlerFunc != nil {
+				c.errorHandlerFunc(item, err)
 			}
+
+			return
 		}
+	}
+
+	id := run.inFlight.add(item)
 
-		if updateHandlePool != nil {
-			updateHandlePool.Go(runInGoroutine)
-			return false, false
+	runHandle := func() {
+		defer run.inFlight.done(id)
+
+		err := c.handle(ctx, run.stopping, item, handlerFunc)
+		if err != nil {
+			run.stop(err)
 		}
+	}
 
-		go runInGoroutine()
-
-		return false, false
-	}
-
-	return handlerFunc(item)
-}
-
-func run[T any](
-	ctx context.Context,
-	updateHandleAsynchronously bool,
-	updateHandlePool *pool.Pool,
-	notifyChannel <-chan T,
-	handlerFunc func(item T) (shouldContinue, shouldBreak bool),
-	panicHandlerFunc func(panicValue *panics.Recovered),
-) context.CancelFunc {
-	ctx, cancel := context.WithCancel(ctx)
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case item, ok := <-notifyChannel:
-				if !ok {
-					break
-				}
-
-				shouldContinue, shouldReturn := runHandle( //nolint: staticcheck
-					item,
-					updateHandleAsynchronously,
-					updateHandlePool,
-					handlerFunc,
-					panicHandlerFunc,
-				)
-				if shouldReturn {
-					return
-				}
-				if shouldContinue {
-					continue
-				}
-			}
+	c.execute(run, runHandle, key, run.keyedQueues != nil)
+}
+
+// execute runs runHandle synchronously or asynchronously. The handlings with the same
+// key are run in order if keyed, which requires the puller to handle asynchronously by
+// key, key is ignored otherwise.
+func (c *Puller[T]) execute(run *pullerRun[T], runHandle func(), key any, keyed bool) {
+	if !c.updateHandleAsynchronously {
+		run.syncMutex.Lock()
+		defer run.syncMutex.Unlock()
+
+		runHandle()
+
+		return
+	}
+
+	runHandleAsynchronously := func() {
+		c.instrumentation.handlerStarted()
+		defer c.instrumentation.handlerFinished()
+
+		runHandle()
+	}
+
+	runWorker := runHandleAsynchronously
+
+	if keyed {
+		// the item is queued to the worker handling the same key if there is one.
+		if !run.keyedQueues.push(key, runHandleAsynchronously) {
+			return
 		}
-	}()
 
-	return cancel
-}
-
-func runWithTicker[T any](
-	ctx context.Context,
-	updateHandleAsynchronously bool,
-	updateHandlePool *pool.Pool,
-	tickerChannel <-chan time.Time,
-	fromFunc func(time.Time) T,
-	handlerFunc func(item T) (shouldContinue, shouldBreak bool),
-	panicHandlerFunc func(panicValue *panics.Recovered),
-) context.CancelFunc {
-	ctx, cancel := context.WithCancel(ctx)
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-tickerChannel:
-				item := fromFunc(time.Now())
-
-				shouldContinue, shouldReturn := runHandle( //nolint: staticcheck
-					item,
-					updateHandleAsynchronously,
-					updateHandlePool,
-					handlerFunc,
-					panicHandlerFunc,
-				)
-				if shouldReturn {
-					return
-				}
-				if shouldContinue {
-					continue
-				}
-			}
+		runWorker = func() {
+			run.keyedQueues.run(key)
 		}
-	}()
+	}
+	if run.handlePool != nil {
+		run.handlePool.Go(runWorker)
+	} else {
+		go runWorker()
+	}
+}
+
+// inFlightItems tracks the items being handled.
+type inFlightItems[T any] struct {
+	mutex  sync.Mutex
+	wg     sync.WaitGroup
+	nextID uint64
+	items  map[uint64]T
+}
+
+func newInFlightItems[T any]() *inFlightItems[T] {
+	return &inFlightItems[T]{items: make(map[uint64]T)}
+}
+
+func (f *inFlightItems[T]) add(item T) uint64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.wg.Add(1)
+
+	id := f.nextID
+	f.nextID++
+	f.items[id] = item
+
+	return id
+}
+
+func (f *inFlightItems[T]) done(id uint64) {
+	f.mutex.Lock()
+	delete(f.items, id)
+	f.mutex.Unlock()
+
+	f.wg.Done()
+}
+
+func (f *inFlightItems[T]) wait() {
+	f.wg.Wait()
+}
+
+// snapshot returns the items being handled in the order of being pulled.
+func (f *inFlightItems[T]) snapshot() []T {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	ids := make([]uint64, 0, len(f.items))
+	for id := range f.items {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	items := make([]T, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, f.items[id])
+	}
 
-	return cancel
+	return items
 }
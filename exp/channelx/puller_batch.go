@@ -0,0 +1,269 @@
+package channelx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/conc/panics"
+)
+
+// WithBatchHandler assigns handler to handle the items in batches. A batch is handled once
+// it reaches size items, or maxWait elapses since the first item of the batch is pulled, the
+// batches are only handled by size if maxWait is zero. The batch pending when the puller stops
+// is handled before StopPull returns.
+//
+// The batches are handled synchronously unless WithHandleAsynchronously is assigned, the panics
+// of handler are passed to the panic handler assigned by WithPanicHandler. The items of the
+// sources with their own handlers are not batched. It panics if size is less than 1.
+func (p *Puller[T]) WithBatchHandler(size int, maxWait time.Duration, handler func(items []T)) *Puller[T] {
+	if size < 1 {
+		panic("batch size of the puller must be greater than zero")
+	}
+
+	p.batchHandlerFunc = handler
+	p.batchSize = size
+	p.batchMaxWait = maxWait
+
+	return p
+}
+
+// WithDebounce coalesces the items pulled in bursts, an item is handled only after no more
+// items are pulled from the same source for wait. The pending item is replaced by the newer
+// item if merge is nil, or by the result of merge otherwise. The items pending when the puller
+// stops are handled before StopPull returns.
+//
+// The debounced items are handled by the handlers, or by the batch handler assigned by
+// WithBatchHandler.
+func (p *Puller[T]) WithDebounce(wait time.Duration, merge func(pending, item T) T) *Puller[T] {
+	p.debounceWait = wait
+	p.debounceMergeFunc = merge
+
+	return p
+}
+
+// dispatchBatch handles the batch synchronously or asynchronously.
+func (c *Puller[T]) dispatchBatch(run *pullerRun[T], ctx context.Context, items []T) {
+	ids := make([]uint64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, run.inFlight.add(item))
+	}
+
+	runHandle := func() {
+		defer func() {
+			for _, id := range ids {
+				run.inFlight.done(id)
+			}
+		}()
+
+		ctx, span := c.instrumentation.startBatchSpan(ctx, len(items))
+		if span != nil {
+			defer span.End()
+		}
+
+		var pc panics.Catcher
+
+		startedAt := time.Now()
+
+		pc.Try(func() {
+			c.batchHandlerFunc(items)
+		})
+
+		recovered := pc.Recovered()
+		c.instrumentation.attemptFinished(ctx, startedAt, recovered != nil)
+		c.instrumentation.batchHandled(ctx, span, len(items), recovered.AsError())
+
+		if recovered != nil && c.panicHandlerFunc != nil {
+			c.panicHandlerFunc(recovered)
+		}
+	}
+
+	// the batches may hold the items of different keys, so they are never ordered by key.
+	c.execute(run, runHandle, nil, false)
+}
+
+// batcher collects the items into batches by size and by time.
+type batcher[T any] struct {
+	mutex      sync.Mutex
+	size       int
+	maxWait    time.Duration
+	items      []T
+	timer      *time.Timer
+	generation uint64
+	closed     bool
+	flushing   sync.WaitGroup
+
+	flushFunc func(items []T)
+}
+
+func newBatcher[T any](size int, maxWait time.Duration, flushFunc func(items []T)) *batcher[T] {
+	return &batcher[T]{size: size, maxWait: maxWait, flushFunc: flushFunc}
+}
+
+func (b *batcher[T]) add(item T) {
+	b.mutex.Lock()
+
+	b.items = append(b.items, item)
+	if len(b.items) >= b.size {
+		items := b.take()
+		b.mutex.Unlock()
+
+		b.flushFunc(items)
+
+		return
+	}
+	if len(b.items) == 1 && b.maxWait > 0 {
+		generation := b.generation
+		b.timer = time.AfterFunc(b.maxWait, func() {
+			b.flushTimeout(generation)
+		})
+	}
+
+	b.mutex.Unlock()
+}
+
+// take takes the items collected, the caller must hold the mutex.
+func (b *batcher[T]) take() []T {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	b.generation++
+
+	items := b.items
+	b.items = nil
+
+	return items
+}
+
+// flushTimeout flushes the batch started in generation when maxWait elapses.
+func (b *batcher[T]) flushTimeout(generation uint64) {
+	b.mutex.Lock()
+	if b.closed || generation != b.generation || len(b.items) == 0 {
+		b.mutex.Unlock()
+		return
+	}
+
+	items := b.take()
+
+	b.flushing.Add(1)
+	defer b.flushing.Done()
+
+	b.mutex.Unlock()
+
+	b.flushFunc(items)
+}
+
+// close flushes the pending batch, and waits for the batches being flushed by the timers.
+func (b *batcher[T]) close() {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	b.closed = true
+	items := b.take()
+	b.mutex.Unlock()
+
+	if len(items) > 0 {
+		b.flushFunc(items)
+	}
+
+	b.flushing.Wait()
+}
+
+type debouncedItem[T any] struct {
+	item       T
+	timer      *time.Timer
+	generation uint64
+}
+
+// debouncer coalesces the items by source until no more items are added for wait.
+type debouncer[T any] struct {
+	mutex      sync.Mutex
+	wait       time.Duration
+	mergeFunc  func(pending, item T) T
+	pending    map[*pullerSource[T]]*debouncedItem[T]
+	generation uint64
+	closed     bool
+	flushing   sync.WaitGroup
+
+	flushFunc func(item T, source *pullerSource[T])
+}
+
+func newDebouncer[T any](wait time.Duration, mergeFunc func(pending, item T) T, flushFunc func(item T, source *pullerSource[T])) *debouncer[T] {
+	return &debouncer[T]{
+		wait:      wait,
+		mergeFunc: mergeFunc,
+		pending:   make(map[*pullerSource[T]]*debouncedItem[T]),
+		flushFunc: flushFunc,
+	}
+}
+
+func (d *debouncer[T]) add(item T, source *pullerSource[T]) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	pending, ok := d.pending[source]
+
+	switch {
+	case !ok:
+		pending = &debouncedItem[T]{item: item}
+		d.pending[source] = pending
+	case d.mergeFunc != nil:
+		pending.timer.Stop()
+		pending.item = d.mergeFunc(pending.item, item)
+	default:
+		pending.timer.Stop()
+		pending.item = item
+	}
+
+	d.generation++
+
+	generation := d.generation
+	pending.generation = generation
+	pending.timer = time.AfterFunc(d.wait, func() {
+		d.flushTimeout(source, generation)
+	})
+}
+
+// flushTimeout flushes the item pending for source if no more items are added since generation.
+func (d *debouncer[T]) flushTimeout(source *pullerSource[T], generation uint64) {
+	d.mutex.Lock()
+
+	pending, ok := d.pending[source]
+	if d.closed || !ok || pending.generation != generation {
+		d.mutex.Unlock()
+		return
+	}
+
+	delete(d.pending, source)
+
+	d.flushing.Add(1)
+	defer d.flushing.Done()
+
+	d.mutex.Unlock()
+
+	d.flushFunc(pending.item, source)
+}
+
+// close flushes the pending items, and waits for the items being flushed by the timers.
+func (d *debouncer[T]) close() {
+	if d == nil {
+		return
+	}
+
+	d.mutex.Lock()
+	d.closed = true
+	pending := d.pending
+	d.pending = make(map[*pullerSource[T]]*debouncedItem[T])
+	d.mutex.Unlock()
+
+	for source, p := range pending {
+		p.timer.Stop()
+		d.flushFunc(p.item, source)
+	}
+
+	d.flushing.Wait()
+}
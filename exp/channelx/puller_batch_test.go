@@ -0,0 +1,225 @@
+package channelx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPuller_WithBatchHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("BySize", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		batches := make([][]int, 0)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithBatchHandler(3, time.Hour, func(items []int) {
+				batches = append(batches, items)
+			})
+		puller.StartPull(context.Background())
+
+		for i := 0; i < 10; i++ {
+			itemChan <- i
+		}
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		// the pending batch is handled when the puller stops.
+		assert.Equal(t, [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}, {9}}, batches)
+	})
+
+	t.Run("ByMaxWait", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		batchChan := make(chan []int, 10)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithBatchHandler(10, time.Millisecond*20, func(items []int) {
+				batchChan <- items
+			})
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+		itemChan <- 2
+
+		select {
+		case batch := <-batchChan:
+			assert.Equal(t, []int{1, 2}, batch)
+		case <-time.After(time.Second):
+			require.FailNow(t, "batch not handled after max wait")
+		}
+
+		itemChan <- 3
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{3}, <-batchChan)
+	})
+
+	t.Run("Asynchronously", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+
+		var mutex sync.Mutex
+
+		handled := make([]int, 0)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithBatchHandler(4, 0, func(items []int) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				assert.LessOrEqual(t, len(items), 4)
+				handled = append(handled, items...)
+			}).
+			WithHandleAsynchronouslyMaxGoroutine(2)
+		puller.StartPull(context.Background())
+
+		for i := 0; i < 10; i++ {
+			itemChan <- i
+		}
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, handled)
+	})
+
+	t.Run("SourceHandler", func(t *testing.T) {
+		t.Parallel()
+
+		batchedChan := make(chan int)
+		sourceChan := make(chan int)
+
+		var mutex sync.Mutex
+
+		batches := make([][]int, 0)
+		sourceItems := make([]int, 0)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(batchedChan).
+			WithNotifyChannel(sourceChan, WithSourceHandler(func(item int) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				sourceItems = append(sourceItems, item)
+			})).
+			WithBatchHandler(2, 0, func(items []int) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				batches = append(batches, items)
+			})
+		puller.StartPull(context.Background())
+
+		batchedChan <- 1
+		sourceChan <- 2
+		batchedChan <- 3
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]int{{1, 3}}, batches)
+		assert.Equal(t, []int{2}, sourceItems)
+	})
+}
+
+func TestPuller_WithDebounce(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Latest", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		handledChan := make(chan int, 10)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {
+				handledChan <- item
+			}).
+			WithDebounce(time.Millisecond*50, nil)
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+		itemChan <- 2
+		itemChan <- 3
+		assert.Equal(t, 3, <-handledChan)
+
+		itemChan <- 4
+		assert.Equal(t, 4, <-handledChan)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		assert.Empty(t, handledChan)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		handledChan := make(chan int, 10)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {
+				handledChan <- item
+			}).
+			WithDebounce(time.Hour, func(pending, item int) int {
+				return pending + item
+			})
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+		itemChan <- 2
+		itemChan <- 3
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		// the pending item is handled when the puller stops.
+		assert.Equal(t, 6, <-handledChan)
+		assert.Empty(t, handledChan)
+	})
+
+	t.Run("WithBatchHandler", func(t *testing.T) {
+		t.Parallel()
+
+		firstChan := make(chan int)
+		secondChan := make(chan int)
+		batchChan := make(chan []int, 10)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(firstChan).
+			WithNotifyChannel(secondChan).
+			WithDebounce(time.Hour, nil).
+			WithBatchHandler(10, 0, func(items []int) {
+				batchChan <- items
+			})
+		puller.StartPull(context.Background())
+
+		firstChan <- 1
+		firstChan <- 2
+		secondChan <- 3
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []int{2, 3}, <-batchChan)
+	})
+}
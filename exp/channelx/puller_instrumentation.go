@@ -0,0 +1,264 @@
+package channelx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/nekomeowww/xo/exp/channelx"
+
+// InstrumentationOption configures the OpenTelemetry instrumentation enabled by
+// Puller.WithInstrumentation.
+type InstrumentationOption func(*instrumentationOptions)
+
+type instrumentationOptions struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider assigns the tracer provider to create the spans, defaults to the
+// global tracer provider.
+func WithTracerProvider(tracerProvider trace.TracerProvider) InstrumentationOption {
+	return func(o *instrumentationOptions) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// WithMeterProvider assigns the meter provider to create the instruments, defaults to
+// the global meter provider.
+func WithMeterProvider(meterProvider metric.MeterProvider) InstrumentationOption {
+	return func(o *instrumentationOptions) {
+		o.meterProvider = meterProvider
+	}
+}
+
+// WithInstrumentation enables the OpenTelemetry instrumentation of the puller, name is
+// used as the span name and recorded as the puller.name attribute. Once enabled, the
+// puller records:
+//
+//   - a span for every handled item, see WithSpanParentExtractor to assign the parent span,
+//     or for every batch handled by the batch handler assigned by WithBatchHandler, with the
+//     number of the items as the puller.batch.size attribute;
+//   - channelx.puller.items.received, the counter of the items pulled from the sources;
+//   - channelx.puller.items.handled, the counter of the items handled successfully;
+//   - channelx.puller.items.failed, the counter of the items failed after the retries;
+//   - channelx.puller.items.panicked, the counter of the panics of the handlers;
+//   - channelx.puller.handle.duration, the histogram of the handler latency in seconds, the
+//     batch handler is recorded once per batch;
+//   - channelx.puller.handlers.in_flight, the gauge of the running asynchronous handlers.
+func (p *Puller[T]) WithInstrumentation(name string, opts ...InstrumentationOption) *Puller[T] {
+	options := new(instrumentationOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	p.instrumentation = newPullerInstrumentation(name, options)
+
+	return p
+}
+
+// WithSpanParentExtractor assigns extractor to return the context holding the parent of the
+// span created for the item, for example, the span context propagated along with the item.
+// It only takes effect when the instrumentation is enabled by WithInstrumentation.
+func (p *Puller[T]) WithSpanParentExtractor(extractor func(ctx context.Context, item T) context.Context) *Puller[T] {
+	p.spanParentExtractorFunc = extractor
+
+	return p
+}
+
+type pullerInstrumentation struct {
+	name       string
+	attributes metric.MeasurementOption
+	tracer     trace.Tracer
+
+	received metric.Int64Counter
+	handled  metric.Int64Counter
+	failed   metric.Int64Counter
+	panicked metric.Int64Counter
+	duration metric.Float64Histogram
+	inFlight atomic.Int64
+}
+
+func newPullerInstrumentation(name string, opts *instrumentationOptions) *pullerInstrumentation {
+	tracerProvider := opts.tracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+
+	meterProvider := opts.meterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+
+	i := &pullerInstrumentation{
+		name:       name,
+		attributes: metric.WithAttributes(attribute.String("puller.name", name)),
+		tracer:     tracerProvider.Tracer(instrumentationName),
+	}
+
+	err := i.createInstruments(meterProvider.Meter(instrumentationName))
+	if err != nil {
+		otel.Handle(err)
+
+		_ = i.createInstruments(metricnoop.NewMeterProvider().Meter(instrumentationName))
+	}
+
+	return i
+}
+
+func (i *pullerInstrumentation) createInstruments(meter metric.Meter) error {
+	var err error
+
+	i.received, err = meter.Int64Counter("channelx.puller.items.received",
+		metric.WithDescription("Number of items pulled from the sources."),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	i.handled, err = meter.Int64Counter("channelx.puller.items.handled",
+		metric.WithDescription("Number of items handled successfully."),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	i.failed, err = meter.Int64Counter("channelx.puller.items.failed",
+		metric.WithDescription("Number of items failed to be handled after the retries."),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	i.panicked, err = meter.Int64Counter("channelx.puller.items.panicked",
+		metric.WithDescription("Number of panics of the handlers."),
+		metric.WithUnit("{panic}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	i.duration, err = meter.Float64Histogram("channelx.puller.handle.duration",
+		metric.WithDescription("Duration of the handlers handling an item."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.Int64ObservableGauge("channelx.puller.handlers.in_flight",
+		metric.WithDescription("Number of the running asynchronous handlers."),
+		metric.WithUnit("{handler}"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observer.Observe(i.inFlight.Load(), i.attributes)
+			return nil
+		}),
+	)
+
+	return err
+}
+
+func (i *pullerInstrumentation) itemReceived(ctx context.Context) {
+	if i == nil {
+		return
+	}
+
+	i.received.Add(ctx, 1, i.attributes)
+}
+
+func (i *pullerInstrumentation) handlerStarted() {
+	if i == nil {
+		return
+	}
+
+	i.inFlight.Add(1)
+}
+
+func (i *pullerInstrumentation) handlerFinished() {
+	if i == nil {
+		return
+	}
+
+	i.inFlight.Add(-1)
+}
+
+func (i *pullerInstrumentation) startSpan(ctx context.Context) (context.Context, trace.Span) {
+	if i == nil {
+		return ctx, nil
+	}
+
+	return i.tracer.Start(ctx, i.name, trace.WithAttributes(attribute.String("puller.name", i.name)))
+}
+
+func (i *pullerInstrumentation) startBatchSpan(ctx context.Context, size int) (context.Context, trace.Span) {
+	if i == nil {
+		return ctx, nil
+	}
+
+	return i.tracer.Start(ctx, i.name, trace.WithAttributes(
+		attribute.String("puller.name", i.name),
+		attribute.Int("puller.batch.size", size),
+	))
+}
+
+func (i *pullerInstrumentation) attemptFinished(ctx context.Context, startedAt time.Time, panicked bool) {
+	if i == nil {
+		return
+	}
+
+	i.duration.Record(ctx, time.Since(startedAt).Seconds(), i.attributes)
+
+	if panicked {
+		i.panicked.Add(ctx, 1, i.attributes)
+	}
+}
+
+func (i *pullerInstrumentation) itemHandled(ctx context.Context, span trace.Span, attempts int, err error) {
+	if i == nil {
+		return
+	}
+
+	span.SetAttributes(attribute.Int("puller.attempts", attempts))
+
+	if err != nil && !errors.Is(err, ErrStopPulling) {
+		i.failed.Add(ctx, 1, i.attributes)
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return
+	}
+
+	i.handled.Add(ctx, 1, i.attributes)
+}
+
+// batchHandled records the items of the batch as handled, or as failed if the batch handler
+// returns err.
+func (i *pullerInstrumentation) batchHandled(ctx context.Context, span trace.Span, size int, err error) {
+	if i == nil {
+		return
+	}
+
+	if err != nil {
+		i.failed.Add(ctx, int64(size), i.attributes)
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return
+	}
+
+	i.handled.Add(ctx, int64(size), i.attributes)
+}
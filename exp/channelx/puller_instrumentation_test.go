@@ -0,0 +1,243 @@
+package channelx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func findMetric(t *testing.T, resourceMetrics metricdata.ResourceMetrics, name string) metricdata.Metrics {
+	t.Helper()
+
+	for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+		for _, m := range scopeMetrics.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+
+	require.FailNow(t, "metric not found", name)
+
+	return metricdata.Metrics{}
+}
+
+func sumOf(t *testing.T, resourceMetrics metricdata.ResourceMetrics, name string) int64 {
+	t.Helper()
+
+	sum, ok := findMetric(t, resourceMetrics, name).Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+
+	var value int64
+	for _, dataPoint := range sum.DataPoints {
+		value += dataPoint.Value
+	}
+
+	return value
+}
+
+type tracedItem struct {
+	value       int
+	spanContext trace.SpanContext
+}
+
+func TestPuller_WithInstrumentation(t *testing.T) {
+	t.Parallel()
+
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(spanRecorder),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	_, parentSpan := tracerProvider.Tracer("test").Start(context.Background(), "produce")
+	parentSpan.End()
+
+	itemChan := make(chan tracedItem)
+	handlerErr := errors.New("failed")
+	inFlightChan := make(chan int64, 1)
+	resumeChan := make(chan struct{})
+
+	puller := NewPuller[tracedItem]().
+		WithNotifyChannel(itemChan).
+		WithHandlerContext(func(ctx context.Context, item tracedItem) error {
+			assert.True(t, trace.SpanFromContext(ctx).IsRecording())
+
+			switch item.value {
+			case 1:
+				panic("panic")
+			case 2:
+				return handlerErr
+			case 3:
+				var resourceMetrics metricdata.ResourceMetrics
+				assert.NoError(t, reader.Collect(context.Background(), &resourceMetrics))
+
+				gauge, ok := findMetric(t, resourceMetrics, "channelx.puller.handlers.in_flight").Data.(metricdata.Gauge[int64])
+				assert.True(t, ok)
+				assert.Len(t, gauge.DataPoints, 1)
+
+				inFlightChan <- gauge.DataPoints[0].Value
+
+				<-resumeChan
+			}
+
+			return nil
+		}).
+		WithHandleAsynchronously().
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1}).
+		WithInstrumentation("test-puller", WithTracerProvider(tracerProvider), WithMeterProvider(meterProvider)).
+		WithSpanParentExtractor(func(ctx context.Context, item tracedItem) context.Context {
+			return trace.ContextWithSpanContext(ctx, item.spanContext)
+		})
+	puller.StartPull(context.Background())
+
+	itemChan <- tracedItem{value: 0, spanContext: parentSpan.SpanContext()}
+	itemChan <- tracedItem{value: 1}
+	itemChan <- tracedItem{value: 2}
+	itemChan <- tracedItem{value: 3}
+
+	assert.GreaterOrEqual(t, <-inFlightChan, int64(1))
+	close(resumeChan)
+
+	err := puller.StopPull(context.Background())
+	require.NoError(t, err)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 5)
+
+	spansByStatus := make(map[codes.Code]int)
+	childSpans := 0
+
+	for _, span := range spans {
+		if span.Name() == "produce" {
+			continue
+		}
+
+		assert.Equal(t, "test-puller", span.Name())
+		spansByStatus[span.Status().Code]++
+
+		if span.Parent().IsValid() {
+			childSpans++
+
+			assert.Equal(t, parentSpan.SpanContext().SpanID(), span.Parent().SpanID())
+		}
+	}
+
+	assert.Equal(t, 1, childSpans)
+	assert.Equal(t, map[codes.Code]int{codes.Unset: 2, codes.Error: 2}, spansByStatus)
+
+	var resourceMetrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &resourceMetrics))
+
+	assert.Equal(t, int64(4), sumOf(t, resourceMetrics, "channelx.puller.items.received"))
+	assert.Equal(t, int64(2), sumOf(t, resourceMetrics, "channelx.puller.items.handled"))
+	assert.Equal(t, int64(2), sumOf(t, resourceMetrics, "channelx.puller.items.failed"))
+	assert.Equal(t, int64(2), sumOf(t, resourceMetrics, "channelx.puller.items.panicked"))
+
+	histogram, ok := findMetric(t, resourceMetrics, "channelx.puller.handle.duration").Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(6), histogram.DataPoints[0].Count)
+
+	gauge, ok := findMetric(t, resourceMetrics, "channelx.puller.handlers.in_flight").Data.(metricdata.Gauge[int64])
+	require.True(t, ok)
+	require.Len(t, gauge.DataPoints, 1)
+	assert.Equal(t, int64(0), gauge.DataPoints[0].Value)
+}
+
+func TestPuller_WithInstrumentationBatch(t *testing.T) {
+	t.Parallel()
+
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(spanRecorder),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	itemChan := make(chan int)
+
+	puller := NewPuller[int]().
+		WithNotifyChannel(itemChan).
+		WithBatchHandler(2, 0, func(items []int) {
+			if items[0] == 2 {
+				panic("panic")
+			}
+		}).
+		WithInstrumentation("test-puller", WithTracerProvider(tracerProvider), WithMeterProvider(meterProvider))
+	puller.StartPull(context.Background())
+
+	for item := range 5 {
+		itemChan <- item
+	}
+
+	err := puller.StopPull(context.Background())
+	require.NoError(t, err)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 3)
+
+	sizes := make([]int64, 0, len(spans))
+	statuses := make([]codes.Code, 0, len(spans))
+
+	for _, span := range spans {
+		assert.Equal(t, "test-puller", span.Name())
+
+		for _, attr := range span.Attributes() {
+			if attr.Key == "puller.batch.size" {
+				sizes = append(sizes, attr.Value.AsInt64())
+			}
+		}
+
+		statuses = append(statuses, span.Status().Code)
+	}
+
+	assert.Equal(t, []int64{2, 2, 1}, sizes)
+	assert.Equal(t, []codes.Code{codes.Unset, codes.Error, codes.Unset}, statuses)
+
+	var resourceMetrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &resourceMetrics))
+
+	assert.Equal(t, int64(5), sumOf(t, resourceMetrics, "channelx.puller.items.received"))
+	assert.Equal(t, int64(3), sumOf(t, resourceMetrics, "channelx.puller.items.handled"))
+	assert.Equal(t, int64(2), sumOf(t, resourceMetrics, "channelx.puller.items.failed"))
+	assert.Equal(t, int64(1), sumOf(t, resourceMetrics, "channelx.puller.items.panicked"))
+
+	histogram, ok := findMetric(t, resourceMetrics, "channelx.puller.handle.duration").Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(3), histogram.DataPoints[0].Count)
+}
+
+func TestPuller_WithoutInstrumentation(t *testing.T) {
+	t.Parallel()
+
+	itemChan := make(chan int)
+	handledChan := make(chan bool, 1)
+
+	puller := NewPuller[int]().
+		WithNotifyChannel(itemChan).
+		WithHandlerContext(func(ctx context.Context, item int) error {
+			handledChan <- trace.SpanFromContext(ctx).SpanContext().IsValid()
+			return nil
+		})
+	puller.StartPull(context.Background())
+
+	itemChan <- 1
+	assert.False(t, <-handledChan)
+
+	err := puller.StopPull(context.Background())
+	require.NoError(t, err)
+}
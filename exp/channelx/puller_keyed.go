@@ -0,0 +1,90 @@
+package channelx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUncomparableKey is passed to the error handler assigned by WithErrorHandler when the
+// key returned by the keyFunc of WithHandleAsynchronouslyByKey holds an uncomparable value.
+var ErrUncomparableKey = errors.New("channelx: key of the item is not comparable")
+
+// WithHandleAsynchronouslyByKey makes the handler of p to be handled asynchronously while
+// keeping the order of the items with the same key returned by keyFunc: the items with the
+// same key are handled sequentially in the order of being pulled, and the items with different
+// keys are handled in parallel. It can be combined with WithHandleAsynchronouslyMaxGoroutine
+// to limit the number of keys being handled at the same time.
+//
+// When K is an interface type, the keys holding uncomparable values, such as slices, maps and
+// functions, are rejected: the item is not handled but passed to the error handler assigned by
+// WithErrorHandler along with ErrUncomparableKey.
+//
+// The batches of the batch handler assigned by WithBatchHandler may hold the items of different
+// keys, so they are not ordered by key, but handled asynchronously in parallel as if assigned by
+// WithHandleAsynchronously.
+//
+// It is a function rather than a method of Puller since methods cannot have type parameters.
+func WithHandleAsynchronouslyByKey[T any, K comparable](p *Puller[T], keyFunc func(item T) K) *Puller[T] {
+	p.WithHandleAsynchronously()
+
+	p.keyFunc = func(item T) (any, error) {
+		key := keyFunc(item)
+
+		value := reflect.ValueOf(key)
+		if value.IsValid() && !value.Comparable() {
+			return nil, fmt.Errorf("%w: %T", ErrUncomparableKey, key)
+		}
+
+		return key, nil
+	}
+
+	return p
+}
+
+// keyedQueues queues the handlings of the items by key, so that the items with the same
+// key are handled by a single worker in order.
+type keyedQueues struct {
+	mutex  sync.Mutex
+	queues map[any][]func()
+}
+
+func newKeyedQueues() *keyedQueues {
+	return &keyedQueues{queues: make(map[any][]func())}
+}
+
+// push appends handle to the queue of key, it returns true if there is no worker handling
+// the key, and the caller has to start one by calling run.
+func (q *keyedQueues) push(key any, handle func()) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	queue, working := q.queues[key]
+	q.queues[key] = append(queue, handle)
+
+	return !working
+}
+
+// run runs the handlings queued for key until the queue is empty.
+func (q *keyedQueues) run(key any) {
+	for {
+		q.mutex.Lock()
+
+		queue := q.queues[key]
+		if len(queue) == 0 {
+			delete(q.queues, key)
+			q.mutex.Unlock()
+
+			return
+		}
+
+		handle := queue[0]
+		queue[0] = nil
+		q.queues[key] = queue[1:]
+
+		q.mutex.Unlock()
+
+		handle()
+	}
+}
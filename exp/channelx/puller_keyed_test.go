@@ -0,0 +1,174 @@
+package channelx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keyedItem struct {
+	key string
+	seq int
+}
+
+func TestWithHandleAsynchronouslyByKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		maxGoroutine int
+	}{
+		{name: "Unbounded"},
+		{name: "MaxGoroutine", maxGoroutine: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			keys := []string{"a", "b", "c", "d"}
+			itemChan := make(chan keyedItem)
+
+			var mutex sync.Mutex
+
+			handled := make(map[string][]int)
+			handling := make(map[string]bool)
+
+			var running, maxRunning atomic.Int64
+
+			puller := NewPuller[keyedItem]().
+				WithNotifyChannel(itemChan).
+				WithHandler(func(item keyedItem) {
+					mutex.Lock()
+					assert.False(t, handling[item.key], "items of the key %s are handled concurrently", item.key)
+					handling[item.key] = true
+					mutex.Unlock()
+
+					current := running.Add(1)
+					for {
+						observed := maxRunning.Load()
+						if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+							break
+						}
+					}
+
+					time.Sleep(time.Millisecond)
+					running.Add(-1)
+
+					mutex.Lock()
+					handling[item.key] = false
+					handled[item.key] = append(handled[item.key], item.seq)
+					mutex.Unlock()
+				})
+			if tc.maxGoroutine > 0 {
+				puller.WithHandleAsynchronouslyMaxGoroutine(tc.maxGoroutine)
+			}
+
+			WithHandleAsynchronouslyByKey(puller, func(item keyedItem) string {
+				return item.key
+			})
+			puller.StartPull(context.Background())
+
+			for seq := 0; seq < 10; seq++ {
+				for _, key := range keys {
+					itemChan <- keyedItem{key: key, seq: seq}
+				}
+			}
+
+			err := puller.StopPull(context.Background())
+			require.NoError(t, err)
+
+			for _, key := range keys {
+				assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, handled[key], "key %s", key)
+			}
+
+			assert.Greater(t, maxRunning.Load(), int64(1))
+
+			if tc.maxGoroutine > 0 {
+				assert.LessOrEqual(t, maxRunning.Load(), int64(tc.maxGoroutine))
+			}
+		})
+	}
+}
+
+func TestWithHandleAsynchronouslyByKey_UncomparableKey(t *testing.T) {
+	t.Parallel()
+
+	itemChan := make(chan int)
+	errorChan := make(chan error, 10)
+
+	var handled atomic.Int64
+
+	puller := NewPuller[int]().
+		WithNotifyChannel(itemChan).
+		WithHandler(func(int) {
+			handled.Add(1)
+		}).
+		WithErrorHandler(func(_ int, err error) {
+			errorChan <- err
+		})
+
+	WithHandleAsynchronouslyByKey(puller, func(item int) any {
+		if item%2 == 0 {
+			return []int{item}
+		}
+
+		return item
+	})
+	puller.StartPull(context.Background())
+
+	for item := range 4 {
+		itemChan <- item
+	}
+
+	err := puller.StopPull(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), handled.Load())
+	require.Len(t, errorChan, 2)
+	assert.ErrorIs(t, <-errorChan, ErrUncomparableKey)
+	assert.ErrorIs(t, <-errorChan, ErrUncomparableKey)
+}
+
+func TestWithHandleAsynchronouslyByKey_Batch(t *testing.T) {
+	t.Parallel()
+
+	itemChan := make(chan keyedItem)
+
+	var running, maxRunning atomic.Int64
+
+	puller := NewPuller[keyedItem]().
+		WithNotifyChannel(itemChan).
+		WithBatchHandler(2, 0, func([]keyedItem) {
+			current := running.Add(1)
+			for {
+				observed := maxRunning.Load()
+				if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+		})
+
+	WithHandleAsynchronouslyByKey(puller, func(item keyedItem) string {
+		return item.key
+	})
+	puller.StartPull(context.Background())
+
+	for seq := range 8 {
+		itemChan <- keyedItem{key: "a", seq: seq}
+	}
+
+	err := puller.StopPull(context.Background())
+	require.NoError(t, err)
+
+	// the batches are handled in parallel even though all of the items have the same key.
+	assert.Greater(t, maxRunning.Load(), int64(1))
+}
@@ -0,0 +1,193 @@
+package channelx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sourcegraph/conc/panics"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RetryPolicy defines how the items are handled again when the handlers return errors or panic.
+type RetryPolicy struct {
+	// MaxRetries is the max number of retries after the first attempt.
+	MaxRetries int
+	// Backoff returns how long to wait before the retry, retry starts from 1.
+	// Retries immediately if Backoff is nil, see ConstantBackoff and ExponentialBackoff.
+	Backoff func(retry int) time.Duration
+	// RetryIf reports whether the error should be retried, the panics are passed as
+	// *panics.ErrRecovered. Retries all of the errors if RetryIf is nil.
+	RetryIf func(err error) bool
+}
+
+func (p *RetryPolicy) shouldRetry(retry int, err error) bool {
+	if p == nil || retry > p.MaxRetries {
+		return false
+	}
+	if err == nil || errors.Is(err, ErrStopPulling) {
+		return false
+	}
+	if p.RetryIf != nil {
+		return p.RetryIf(err)
+	}
+
+	return true
+}
+
+// ConstantBackoff returns a backoff for RetryPolicy that always waits for interval.
+func ConstantBackoff(interval time.Duration) func(retry int) time.Duration {
+	return func(int) time.Duration {
+		return interval
+	}
+}
+
+// ExponentialBackoff returns a backoff for RetryPolicy that waits for initial before the first
+// retry, and doubles the wait time for every retry after, the wait time is capped at maxWait.
+func ExponentialBackoff(initial, maxWait time.Duration) func(retry int) time.Duration {
+	return func(retry int) time.Duration {
+		backoff := initial
+		for i := 1; i < retry && backoff < maxWait; i++ {
+			backoff *= 2
+		}
+		if backoff > maxWait {
+			return maxWait
+		}
+
+		return backoff
+	}
+}
+
+// DeadLetter is an item that failed to be handled after the retries are exhausted.
+type DeadLetter[T any] struct {
+	// Item is the item failed to be handled.
+	Item T
+	// Err is the error of the last attempt, the panics are passed as *panics.ErrRecovered.
+	Err error
+	// Attempts is the number of attempts to handle the item, including the retries.
+	Attempts int
+}
+
+// handle handles the item with the retries, and reports the error to the error handler
+// and the dead letter handlers. It returns the error wrapping ErrStopPulling if the
+// handler asks the puller to stop pulling items. stopping is closed once the puller
+// stops pulling items.
+func (c *Puller[T]) handle(ctx context.Context, stopping <-chan struct{}, item T, handlerFunc func(ctx context.Context, item T) error) error {
+	ctx, span := c.startSpan(ctx, item)
+	if span != nil {
+		defer span.End()
+	}
+
+	attempts := 1
+	err := c.tryHandle(ctx, item, handlerFunc)
+
+	for c.retryPolicy.shouldRetry(attempts, err) {
+		if c.retryPolicy.Backoff != nil && !sleepContext(ctx, c.retryPolicy.Backoff(attempts)) {
+			break
+		}
+
+		attempts++
+		err = c.tryHandle(ctx, item, handlerFunc)
+	}
+
+	c.instrumentation.itemHandled(ctx, span, attempts, err)
+
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, ErrStopPulling) {
+		return err
+	}
+	if c.errorHandlerFunc != nil {
+		c.errorHandlerFunc(item, err)
+	}
+
+	letter := DeadLetter[T]{Item: item, Err: err, Attempts: attempts}
+
+	if c.deadLetterHandlerFunc != nil {
+		c.deadLetterHandlerFunc(letter)
+	}
+	if c.deadLetterChan != nil {
+		c.sendDeadLetter(ctx, stopping, letter)
+	}
+
+	return nil
+}
+
+// sendDeadLetter sends the letter to the dead letter channel until ctx is done or the puller
+// stops pulling items, after which the letter is sent only if the channel is ready.
+func (c *Puller[T]) sendDeadLetter(ctx context.Context, stopping <-chan struct{}, letter DeadLetter[T]) {
+	select {
+	case c.deadLetterChan <- letter:
+	case <-ctx.Done():
+	case <-stopping:
+		select {
+		case c.deadLetterChan <- letter:
+		default:
+		}
+	}
+}
+
+func (c *Puller[T]) tryHandle(ctx context.Context, item T, handlerFunc func(ctx context.Context, item T) error) error {
+	if c.handlerTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.handlerTimeout)
+		defer cancel()
+	}
+
+	var err error
+
+	var pc panics.Catcher
+
+	startedAt := time.Now()
+
+	pc.Try(func() {
+		err = handlerFunc(ctx, item)
+	})
+
+	recovered := pc.Recovered()
+	c.instrumentation.attemptFinished(ctx, startedAt, recovered != nil)
+
+	if recovered == nil {
+		return err
+	}
+	if c.panicHandlerFunc != nil {
+		c.panicHandlerFunc(recovered)
+	}
+
+	return recovered.AsError()
+}
+
+// startSpan starts the span of handling the item when the instrumentation is enabled, the
+// parent of the span is extracted by spanParentExtractorFunc if assigned.
+func (c *Puller[T]) startSpan(ctx context.Context, item T) (context.Context, trace.Span) {
+	if c.instrumentation == nil {
+		return ctx, nil
+	}
+	if c.spanParentExtractorFunc != nil {
+		spanContext := trace.SpanContextFromContext(c.spanParentExtractorFunc(ctx, item))
+		if spanContext.IsValid() {
+			ctx = trace.ContextWithSpanContext(ctx, spanContext)
+		}
+	}
+
+	return c.instrumentation.startSpan(ctx)
+}
+
+// sleepContext sleeps for d, it returns false if ctx is done before d elapses.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
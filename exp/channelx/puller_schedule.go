@@ -0,0 +1,224 @@
+package channelx
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Clock provides the time to the scheduled ticker sources, it can be replaced by
+// WithTickerClock to control the time in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the
+	// returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Schedule decides when the scheduled ticker sources pull the items.
+type Schedule interface {
+	// Next returns the time of the next pull after the pull at prev.
+	Next(prev time.Time) time.Time
+}
+
+type everySchedule time.Duration
+
+func (s everySchedule) Next(prev time.Time) time.Time {
+	return prev.Add(time.Duration(s))
+}
+
+// EverySchedule returns a schedule to pull items every interval, it panics if interval is
+// not positive.
+func EverySchedule(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic("interval of the ticker must be greater than zero")
+	}
+
+	return everySchedule(interval)
+}
+
+// ParseCronSchedule parses the standard cron expression with five fields, minute, hour,
+// day of month, month and day of week, as well as the descriptors such as @hourly and
+// @every 1m30s. The time zone can be assigned by the CRON_TZ= prefix.
+func ParseCronSchedule(expr string) (Schedule, error) {
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("channelx: failed to parse cron expression %q: %w", expr, err)
+	}
+
+	return schedule, nil
+}
+
+// WithTickerJitter randomly shifts every pull of the scheduled ticker sources away from
+// its scheduled time by up to fraction of the scheduled wait in both directions, for
+// example, a fraction of 0.1 makes the pulls scheduled every 10s happen within 1s around
+// the scheduled times. The schedule itself is not shifted, so the jitter neither drifts
+// the pulls nor fires a tick twice. It panics if fraction is not within [0, 1].
+func WithTickerJitter[T any](fraction float64) PullerSourceOption[T] {
+	if fraction < 0 || fraction > 1 {
+		panic("jitter fraction of the ticker must be within [0, 1]")
+	}
+
+	return func(s *pullerSource[T]) {
+		s.jitter = fraction
+	}
+}
+
+// WithTickerBackoff makes the scheduled ticker sources back off when pullFromFunc returns
+// no item or an error, the wait before the next pull doubles for every consecutive empty
+// pull and is capped at maxWait. The wait is reset to follow the schedule once an item is pulled.
+func WithTickerBackoff[T any](maxWait time.Duration) PullerSourceOption[T] {
+	return func(s *pullerSource[T]) {
+		s.maxBackoff = maxWait
+	}
+}
+
+// WithTickerPullImmediately makes the scheduled ticker sources pull once immediately
+// when the puller starts instead of waiting for the first tick of the schedule.
+func WithTickerPullImmediately[T any]() PullerSourceOption[T] {
+	return func(s *pullerSource[T]) {
+		s.pullImmediately = true
+	}
+}
+
+// WithTickerClock assigns the clock used by the scheduled ticker sources, defaults to
+// the system clock.
+func WithTickerClock[T any](clock Clock) PullerSourceOption[T] {
+	return func(s *pullerSource[T]) {
+		s.clock = clock
+	}
+}
+
+// WithTickerSchedule adds a ticker following the schedule to pull items from, see
+// EverySchedule and ParseCronSchedule. On every tick, the item is created by pullFromFunc,
+// which returns false if there is nothing to pull. The ctx passed to pullFromFunc is
+// cancelled when the puller stops pulling, the errors returned by pullFromFunc are passed
+// to the error handler assigned by WithErrorHandler along with the zero value of T.
+//
+// The source can be configured by WithTickerJitter, WithTickerBackoff, WithTickerPullImmediately
+// and WithTickerClock.
+func (p *Puller[T]) WithTickerSchedule(schedule Schedule, pullFromFunc func(ctx context.Context, tick time.Time) (item T, ok bool, err error), opts ...PullerSourceOption[T]) *Puller[T] {
+	source := newPullerSource(opts)
+	source.schedule = schedule
+	source.scheduledPullFunc = pullFromFunc
+
+	p.sources = append(p.sources, source)
+
+	return p
+}
+
+func (c *Puller[T]) reportPullError(err error) {
+	if c.errorHandlerFunc == nil {
+		return
+	}
+
+	var zero T
+
+	c.errorHandlerFunc(zero, err)
+}
+
+// runSchedule pulls items following the schedule and sends them to itemChan until ctx is done.
+func (s *pullerSource[T]) runSchedule(ctx context.Context, itemChan chan<- T, reportError func(err error)) {
+	clock := s.clock
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	prev := clock.Now()
+	emptyPulls := 0
+
+	pull := func(tick time.Time) bool {
+		item, ok, err := s.scheduledPullFunc(ctx, tick)
+		if err != nil && ctx.Err() == nil {
+			reportError(err)
+		}
+		if err != nil || !ok {
+			emptyPulls++
+			return true
+		}
+
+		emptyPulls = 0
+
+		select {
+		case <-ctx.Done():
+			return false
+		case itemChan <- item:
+			return true
+		}
+	}
+
+	if s.pullImmediately && !pull(prev) {
+		return
+	}
+
+	for {
+		wait := s.nextWait(prev, emptyPulls)
+
+		// the jitter only shifts the pull, the next pull is still scheduled from the
+		// scheduled time, otherwise a schedule such as cron fires twice for the same
+		// tick when the jitter makes the pull happen before the tick.
+		next := prev.Add(wait)
+		now := clock.Now()
+		if next.Before(now) {
+			// the ticks missed while the previous item was being handled are dropped.
+			next = now
+		}
+
+		wait = max(next.Sub(now)+s.jitterOf(wait), 0)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-clock.After(wait):
+		}
+
+		prev = next
+
+		if !pull(now.Add(wait)) {
+			return
+		}
+	}
+}
+
+// nextWait returns the wait between the pull scheduled at prev and the next pull,
+// without the jitter.
+func (s *pullerSource[T]) nextWait(prev time.Time, emptyPulls int) time.Duration {
+	wait := s.schedule.Next(prev).Sub(prev)
+
+	if s.maxBackoff > 0 {
+		for i := 0; i < emptyPulls && wait < s.maxBackoff; i++ {
+			wait *= 2
+		}
+		if emptyPulls > 0 && wait > s.maxBackoff {
+			wait = s.maxBackoff
+		}
+	}
+	if wait < 0 {
+		return 0
+	}
+
+	return wait
+}
+
+// jitterOf returns a random offset of up to the jitter fraction of wait in both directions.
+func (s *pullerSource[T]) jitterOf(wait time.Duration) time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(wait) * s.jitter * (rand.Float64()*2 - 1)) //nolint:gosec
+}
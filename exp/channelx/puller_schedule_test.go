@@ -0,0 +1,313 @@
+package channelx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClockWaiter struct {
+	at   time.Time
+	tick chan time.Time
+}
+
+type fakeClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	waits   []time.Duration
+	waiters []fakeClockWaiter
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	tick := make(chan time.Time, 1)
+	c.waits = append(c.waits, d)
+
+	if d <= 0 {
+		tick <- c.now
+		return tick
+	}
+
+	c.waiters = append(c.waiters, fakeClockWaiter{at: c.now.Add(d), tick: tick})
+
+	return tick
+}
+
+// Advance moves the clock forward by d and fires the waiters due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+
+	waiters := c.waiters[:0]
+
+	for _, waiter := range c.waiters {
+		if waiter.at.After(c.now) {
+			waiters = append(waiters, waiter)
+			continue
+		}
+
+		waiter.tick <- c.now
+	}
+
+	c.waiters = waiters
+}
+
+// WaitForWaits blocks until After has been called n times, and returns the durations passed.
+func (c *fakeClock) WaitForWaits(t *testing.T, n int) []time.Duration {
+	t.Helper()
+
+	var waits []time.Duration
+
+	require.Eventually(t, func() bool {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		waits = append([]time.Duration(nil), c.waits...)
+
+		return len(waits) >= n
+	}, time.Second, time.Millisecond)
+
+	return waits
+}
+
+func TestPuller_WithTickerSchedule(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC)
+
+	t.Run("PullImmediately", func(t *testing.T) {
+		t.Parallel()
+
+		clock := newFakeClock(start)
+		ticks := make(chan time.Time, 10)
+
+		puller := NewPuller[time.Time]().
+			WithTickerSchedule(EverySchedule(time.Minute), func(_ context.Context, tick time.Time) (time.Time, bool, error) {
+				return tick, true, nil
+			}, WithTickerPullImmediately[time.Time](), WithTickerClock[time.Time](clock)).
+			WithHandler(func(item time.Time) {
+				ticks <- item
+			})
+		puller.StartPull(context.Background())
+
+		assert.Equal(t, start, <-ticks)
+		assert.Equal(t, []time.Duration{time.Minute}, clock.WaitForWaits(t, 1))
+
+		clock.Advance(time.Minute)
+		assert.Equal(t, start.Add(time.Minute), <-ticks)
+		assert.Equal(t, []time.Duration{time.Minute, time.Minute}, clock.WaitForWaits(t, 2))
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		t.Parallel()
+
+		clock := newFakeClock(start)
+		pullErr := errors.New("failed")
+		errorChan := make(chan error, 10)
+		items := make(chan int, 10)
+
+		pulls := 0
+
+		puller := NewPuller[int]().
+			WithTickerSchedule(EverySchedule(time.Second), func(_ context.Context, _ time.Time) (int, bool, error) {
+				pulls++
+
+				switch pulls {
+				case 1, 3:
+					return 0, false, nil
+				case 2:
+					return 0, false, pullErr
+				default:
+					return pulls, true, nil
+				}
+			}, WithTickerBackoff[int](5*time.Second), WithTickerClock[int](clock)).
+			WithHandler(func(item int) {
+				items <- item
+			}).
+			WithErrorHandler(func(_ int, err error) {
+				errorChan <- err
+			})
+		puller.StartPull(context.Background())
+
+		expectedWaits := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, time.Second}
+		for i, wait := range expectedWaits[:4] {
+			assert.Equal(t, expectedWaits[:i+1], clock.WaitForWaits(t, i+1))
+			clock.Advance(wait)
+		}
+
+		assert.Equal(t, 4, <-items)
+		assert.Equal(t, expectedWaits, clock.WaitForWaits(t, 5))
+		assert.ErrorIs(t, <-errorChan, pullErr)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("Cron", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := ParseCronSchedule("*/5 * * * *")
+		require.NoError(t, err)
+
+		clock := newFakeClock(start)
+		ticks := make(chan time.Time, 10)
+
+		puller := NewPuller[time.Time]().
+			WithTickerSchedule(schedule, func(_ context.Context, tick time.Time) (time.Time, bool, error) {
+				return tick, true, nil
+			}, WithTickerClock[time.Time](clock)).
+			WithHandler(func(item time.Time) {
+				ticks <- item
+			})
+		puller.StartPull(context.Background())
+
+		assert.Equal(t, []time.Duration{4 * time.Minute}, clock.WaitForWaits(t, 1))
+		clock.Advance(4 * time.Minute)
+		assert.Equal(t, time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC), <-ticks)
+		assert.Equal(t, []time.Duration{4 * time.Minute, 5 * time.Minute}, clock.WaitForWaits(t, 2))
+
+		err = puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("InvalidCron", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseCronSchedule("* * *")
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidInterval", func(t *testing.T) {
+		t.Parallel()
+
+		for _, interval := range []time.Duration{0, -time.Second} {
+			assert.Panics(t, func() {
+				NewPuller[int]().WithTickerInterval(interval, func(time.Time) int {
+					return 0
+				})
+			})
+		}
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		t.Parallel()
+
+		clock := newFakeClock(start)
+
+		puller := NewPuller[int]().
+			WithTickerSchedule(EverySchedule(10*time.Second), func(_ context.Context, _ time.Time) (int, bool, error) {
+				return 0, true, nil
+			}, WithTickerJitter[int](0.1), WithTickerClock[int](clock))
+		puller.StartPull(context.Background())
+
+		for i := 0; i < 20; i++ {
+			waits := clock.WaitForWaits(t, i+1)
+			clock.Advance(waits[i])
+
+			scheduled := start.Add(time.Duration(i+1) * 10 * time.Second)
+			assert.InDelta(t, 0, float64(clock.Now().Sub(scheduled)), float64(time.Second))
+		}
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("CronJitter", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := ParseCronSchedule("@hourly")
+		require.NoError(t, err)
+
+		clock := newFakeClock(start)
+		ticks := make(chan time.Time, 100)
+
+		puller := NewPuller[int]().
+			WithTickerSchedule(schedule, func(_ context.Context, tick time.Time) (int, bool, error) {
+				ticks <- tick
+				return 0, false, nil
+			}, WithTickerJitter[int](0.2), WithTickerClock[int](clock))
+		puller.StartPull(context.Background())
+
+		pulls := 0
+		for ; clock.Now().Before(start.Add(24 * time.Hour)); pulls++ {
+			waits := clock.WaitForWaits(t, pulls+1)
+			clock.Advance(waits[pulls])
+		}
+
+		// waits for the last pull to finish.
+		clock.WaitForWaits(t, pulls+1)
+
+		err = puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		hours := make([]time.Time, 0, pulls)
+		for range pulls {
+			hours = append(hours, (<-ticks).Round(time.Hour))
+		}
+
+		// every pull is fired within 12 minutes around the top of a distinct hour.
+		require.GreaterOrEqual(t, len(hours), 23)
+		for i := range hours {
+			assert.Equal(t, time.Date(2024, 1, 1, 13+i, 0, 0, 0, time.UTC), hours[i])
+		}
+	})
+
+	t.Run("MissedTicks", func(t *testing.T) {
+		t.Parallel()
+
+		clock := newFakeClock(start)
+		ticks := make(chan time.Time, 10)
+
+		pulls := 0
+
+		puller := NewPuller[time.Time]().
+			WithTickerSchedule(EverySchedule(time.Second), func(_ context.Context, tick time.Time) (time.Time, bool, error) {
+				pulls++
+				if pulls == 1 {
+					// the first pull takes 3.5s.
+					clock.Advance(3500 * time.Millisecond)
+				}
+
+				return tick, true, nil
+			}, WithTickerClock[time.Time](clock)).
+			WithHandler(func(item time.Time) {
+				ticks <- item
+			})
+		puller.StartPull(context.Background())
+
+		clock.WaitForWaits(t, 1)
+		clock.Advance(time.Second)
+		assert.Equal(t, start.Add(time.Second), <-ticks)
+
+		// the next tick fires immediately once the pull finishes, instead of firing
+		// for every missed tick.
+		assert.Equal(t, start.Add(4500*time.Millisecond), <-ticks)
+		assert.Equal(t, []time.Duration{time.Second, 0, time.Second}, clock.WaitForWaits(t, 3))
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,276 @@
+package channelx
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// PullerSourceOption configures a source of the Puller, it can be passed to
+// WithNotifyChannel, WithTickerChannel and WithTickerInterval.
+type PullerSourceOption[T any] func(*pullerSource[T])
+
+// WithSourcePriority assigns the priority of the source. When items of several sources
+// are ready at the same time, the items of the source with higher priority are pulled
+// first. Sources default to the priority 0, the sources with the same priority are
+// pulled in random order.
+func WithSourcePriority[T any](priority int) PullerSourceOption[T] {
+	return func(s *pullerSource[T]) {
+		s.priority = priority
+	}
+}
+
+// WithSourceHandler assigns handler to handle the items pulled from the source instead
+// of the handler assigned to the puller.
+func WithSourceHandler[T any](handler func(item T)) PullerSourceOption[T] {
+	return func(s *pullerSource[T]) {
+		s.handlerFunc = func(_ context.Context, item T) error {
+			handler(item)
+			return nil
+		}
+	}
+}
+
+// WithSourceHandlerContext assigns handler to handle the items pulled from the source
+// instead of the handler assigned to the puller, see Puller.WithHandlerContext.
+func WithSourceHandlerContext[T any](handler func(ctx context.Context, item T) error) PullerSourceOption[T] {
+	return func(s *pullerSource[T]) {
+		s.handlerFunc = handler
+	}
+}
+
+type pullerSource[T any] struct {
+	notifyChan   <-chan T
+	tickerChan   <-chan time.Time
+	pullFromFunc func(time.Time) T
+
+	schedule          Schedule
+	scheduledPullFunc func(ctx context.Context, tick time.Time) (T, bool, error)
+	jitter            float64
+	maxBackoff        time.Duration
+	pullImmediately   bool
+	clock             Clock
+
+	priority    int
+	handlerFunc func(ctx context.Context, item T) error
+}
+
+func newPullerSource[T any](opts []PullerSourceOption[T]) *pullerSource[T] {
+	s := new(pullerSource[T])
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *pullerSource[T]) isTicker() bool {
+	return s.tickerChan != nil || s.schedule != nil
+}
+
+// start returns the channel to receive items from, ticks of the ticker sources are
+// converted into items in a standalone goroutine until ctx is done or the ticker
+// channel is closed.
+func (s *pullerSource[T]) start(ctx context.Context, reportError func(err error)) reflect.Value {
+	if !s.isTicker() {
+		return reflect.ValueOf(s.notifyChan)
+	}
+
+	itemChan := make(chan T)
+
+	if s.schedule != nil {
+		go s.runSchedule(ctx, itemChan, reportError)
+
+		return reflect.ValueOf((<-chan T)(itemChan))
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-s.tickerChan:
+				if !ok {
+					// the closed ticker channel closes the source, so that it is removed
+					// from the fan-in instead of spinning on the zero ticks.
+					close(itemChan)
+					return
+				}
+
+				item := s.pullFromFunc(time.Now())
+
+				select {
+				case <-ctx.Done():
+					return
+				case itemChan <- item:
+				}
+			}
+		}
+	}()
+
+	return reflect.ValueOf((<-chan T)(itemChan))
+}
+
+// pullerFanIn receives items from several sources with respect to the priorities
+// of the sources.
+type pullerFanIn[T any] struct {
+	sources     []*pullerSource[T]
+	channels    []reflect.Value
+	prioritized bool
+
+	// cases holds the cases of the blocking select, the first case is ctx.Done().
+	cases []reflect.SelectCase
+	// groups holds the cases of the non-blocking selects for each priority, from
+	// the highest priority to the lowest priority, the last case of each group is
+	// the default case.
+	groups [][]reflect.SelectCase
+	// groupOffsets holds the index of the first source of each group.
+	groupOffsets []int
+
+	ctx context.Context
+}
+
+func newPullerFanIn[T any](ctx context.Context, sources []*pullerSource[T], reportError func(err error)) *pullerFanIn[T] {
+	sorted := make([]*pullerSource[T], len(sources))
+	copy(sorted, sources)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].priority > sorted[j].priority
+	})
+
+	f := &pullerFanIn[T]{sources: sorted, channels: make([]reflect.Value, 0, len(sorted)), ctx: ctx}
+
+	for _, s := range sorted {
+		f.channels = append(f.channels, s.start(ctx, reportError))
+
+		if s.priority != sorted[0].priority {
+			f.prioritized = true
+		}
+	}
+
+	f.rebuild()
+
+	return f
+}
+
+func (f *pullerFanIn[T]) rebuild() {
+	f.cases = make([]reflect.SelectCase, 0, len(f.sources)+1)
+	f.cases = append(f.cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(f.ctx.Done())})
+
+	for _, channel := range f.channels {
+		f.cases = append(f.cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: channel})
+	}
+
+	if !f.prioritized {
+		return
+	}
+
+	f.groups = f.groups[:0]
+	f.groupOffsets = f.groupOffsets[:0]
+
+	for i, s := range f.sources {
+		if i == 0 || s.priority != f.sources[i-1].priority {
+			f.groups = append(f.groups, make([]reflect.SelectCase, 0))
+			f.groupOffsets = append(f.groupOffsets, i)
+		}
+
+		last := len(f.groups) - 1
+		f.groups[last] = append(f.groups[last], reflect.SelectCase{Dir: reflect.SelectRecv, Chan: f.channels[i]})
+	}
+
+	for i := range f.groups {
+		f.groups[i] = append(f.groups[i], reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+}
+
+// receive blocks until an item is received from any of the sources, the closed
+// sources are removed. The returned ok is false when ctx is done or all of the
+// sources are closed.
+func (f *pullerFanIn[T]) receive() (item T, source *pullerSource[T], ok bool) {
+	for len(f.sources) > 0 {
+		if f.ctx.Err() != nil {
+			return item, nil, false
+		}
+
+		index := -1
+
+		var value reflect.Value
+
+		var received bool
+
+		if f.prioritized {
+			index, value, received = f.tryReceiveByPriority()
+		}
+		if index == -1 {
+			var chosen int
+
+			chosen, value, received = reflect.Select(f.cases)
+			if chosen == 0 {
+				return item, nil, false
+			}
+
+			index = chosen - 1
+		}
+		if !received {
+			f.remove(index)
+			continue
+		}
+
+		if value.IsValid() {
+			item, _ = value.Interface().(T)
+		}
+
+		return item, f.sources[index], true
+	}
+
+	return item, nil, false
+}
+
+func (f *pullerFanIn[T]) tryReceiveByPriority() (int, reflect.Value, bool) {
+	for i, group := range f.groups {
+		chosen, value, received := reflect.Select(group)
+		if chosen == len(group)-1 {
+			continue
+		}
+
+		return f.groupOffsets[i] + chosen, value, received
+	}
+
+	return -1, reflect.Value{}, false
+}
+
+// tryReceiveBuffered receives an item buffered in the notify channels without blocking,
+// the closed channels are removed. The returned ok is false when none of the notify
+// channels has items buffered.
+func (f *pullerFanIn[T]) tryReceiveBuffered() (item T, source *pullerSource[T], ok bool) {
+	for i := 0; i < len(f.sources); {
+		s := f.sources[i]
+		if s.isTicker() {
+			i++
+			continue
+		}
+
+		value, received := f.channels[i].TryRecv()
+		if !value.IsValid() {
+			i++
+			continue
+		}
+		if !received {
+			f.remove(i)
+			continue
+		}
+
+		item, _ = value.Interface().(T)
+
+		return item, s, true
+	}
+
+	return item, nil, false
+}
+
+func (f *pullerFanIn[T]) remove(index int) {
+	f.sources = append(f.sources[:index:index], f.sources[index+1:]...)
+	f.channels = append(f.channels[:index:index], f.channels[index+1:]...)
+	f.rebuild()
+}
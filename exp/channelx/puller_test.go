@@ -2,10 +2,15 @@ package channelx
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/samber/lo"
 	"github.com/sourcegraph/conc"
 	"github.com/sourcegraph/conc/panics"
 	"github.com/stretchr/testify/assert"
@@ -399,7 +404,7 @@ func TestPuller_WithNotifyChannel(t *testing.T) {
 		assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, handledItems)
 	})
 
-	t.Run("CancelFuncEmpty", func(t *testing.T) {
+	t.Run("ClosedNotifyChannel", func(t *testing.T) {
 		t.Parallel()
 
 		itemChan := make(chan int)
@@ -413,7 +418,6 @@ func TestPuller_WithNotifyChannel(t *testing.T) {
 			})
 
 		puller.StartPull(context.Background())
-		puller.contextCancelFunc = nil
 
 		close(itemChan)
 
@@ -475,8 +479,10 @@ func TestPuller_WithTickerChannel(t *testing.T) {
 		err := puller.StopPull(context.Background())
 		require.NoError(t, err)
 
-		assert.Len(t, handledItems, 10)
-		assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, handledItems)
+		// StopPull waits for the item being handled, which may be the 11th item.
+		require.GreaterOrEqual(t, len(handledItems), 10)
+		assert.LessOrEqual(t, len(handledItems), 11)
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, handledItems[:10])
 	})
 
 	t.Run("WithHandlerWithShouldReturn", func(t *testing.T) {
@@ -510,8 +516,9 @@ func TestPuller_WithTickerChannel(t *testing.T) {
 		err := puller.StopPull(context.Background())
 		require.NoError(t, err)
 
+		// StopPull waits for the item 4 being handled.
 		assert.Len(t, handledItems, 5)
-		assert.ElementsMatch(t, []int{0, 1, 2, 3, 0}, handledItems)
+		assert.ElementsMatch(t, []int{0, 1, 2, 3, 4}, handledItems)
 	})
 
 	t.Run("WithHandlerWithShouldContinue", func(t *testing.T) {
@@ -709,3 +716,965 @@ func TestPuller_WithTickerChannel(t *testing.T) {
 		assert.Equal(t, "github.com/nekomeowww/xo/exp/channelx.TestPuller_WithTickerChannel.func8.1", funcObj.Name())
 	})
 }
+
+func TestPuller_WithMultipleSources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotifyChannelsAndTicker", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan1 := make(chan int)
+		itemChan2 := make(chan int)
+
+		ticker := time.NewTicker(time.Millisecond * 10)
+		defer ticker.Stop()
+
+		var handledItemsMutex sync.Mutex
+
+		handledItems := make([]int, 0)
+		handlerFunc := func(item int) {
+			handledItemsMutex.Lock()
+			defer handledItemsMutex.Unlock()
+
+			handledItems = append(handledItems, item)
+		}
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan1).
+			WithNotifyChannel(itemChan2).
+			WithTickerChannel(ticker.C, func(_ time.Time) int { return -1 }).
+			WithHandler(handlerFunc)
+		puller.StartPull(context.Background())
+
+		for i := 0; i < 5; i++ {
+			itemChan1 <- i
+			itemChan2 <- i + 10
+		}
+
+		assert.Eventually(t, func() bool {
+			handledItemsMutex.Lock()
+			defer handledItemsMutex.Unlock()
+
+			return lo.Contains(handledItems, -1)
+		}, time.Second, time.Millisecond*10)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		handledItemsMutex.Lock()
+		defer handledItemsMutex.Unlock()
+
+		assert.Subset(t, handledItems, []int{0, 1, 2, 3, 4, 10, 11, 12, 13, 14})
+	})
+
+	t.Run("WithSourcePriority", func(t *testing.T) {
+		t.Parallel()
+
+		lowPriorityChan := make(chan int, 3)
+		highPriorityChan := make(chan int, 3)
+
+		for i := 0; i < 3; i++ {
+			lowPriorityChan <- i
+			highPriorityChan <- i + 10
+		}
+
+		var handledItemsMutex sync.Mutex
+
+		handledItems := make([]int, 0)
+		handlerFunc := func(item int) {
+			handledItemsMutex.Lock()
+			defer handledItemsMutex.Unlock()
+
+			handledItems = append(handledItems, item)
+		}
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(lowPriorityChan).
+			WithNotifyChannel(highPriorityChan, WithSourcePriority[int](1)).
+			WithHandler(handlerFunc)
+		puller.StartPull(context.Background())
+
+		assert.Eventually(t, func() bool {
+			handledItemsMutex.Lock()
+			defer handledItemsMutex.Unlock()
+
+			return len(handledItems) == 6
+		}, time.Second, time.Millisecond)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		handledItemsMutex.Lock()
+		defer handledItemsMutex.Unlock()
+
+		assert.Equal(t, []int{10, 11, 12, 0, 1, 2}, handledItems)
+	})
+
+	t.Run("WithSourceHandler", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan1 := make(chan int)
+		itemChan2 := make(chan int)
+
+		var handledItemsMutex sync.Mutex
+
+		handledItems := make([]int, 0)
+		handledItemsFromSource := make([]int, 0)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan1).
+			WithNotifyChannel(itemChan2, WithSourceHandler(func(item int) {
+				handledItemsMutex.Lock()
+				defer handledItemsMutex.Unlock()
+
+				handledItemsFromSource = append(handledItemsFromSource, item)
+			})).
+			WithHandler(func(item int) {
+				handledItemsMutex.Lock()
+				defer handledItemsMutex.Unlock()
+
+				handledItems = append(handledItems, item)
+			})
+		puller.StartPull(context.Background())
+
+		itemChan1 <- 1
+		itemChan2 <- 2
+
+		assert.Eventually(t, func() bool {
+			handledItemsMutex.Lock()
+			defer handledItemsMutex.Unlock()
+
+			return len(handledItems)+len(handledItemsFromSource) == 2
+		}, time.Second, time.Millisecond)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		handledItemsMutex.Lock()
+		defer handledItemsMutex.Unlock()
+
+		assert.Equal(t, []int{1}, handledItems)
+		assert.Equal(t, []int{2}, handledItemsFromSource)
+	})
+
+	t.Run("ClosedSource", func(t *testing.T) {
+		t.Parallel()
+
+		closedChan := make(chan int)
+		close(closedChan)
+
+		itemChan := make(chan int)
+
+		var handledItemsMutex sync.Mutex
+
+		handledItems := make([]int, 0)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(closedChan).
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {
+				handledItemsMutex.Lock()
+				defer handledItemsMutex.Unlock()
+
+				handledItems = append(handledItems, item)
+			})
+		puller.StartPull(context.Background())
+
+		for i := 1; i <= 3; i++ {
+			itemChan <- i
+		}
+
+		assert.Eventually(t, func() bool {
+			handledItemsMutex.Lock()
+			defer handledItemsMutex.Unlock()
+
+			return len(handledItems) == 3
+		}, time.Second, time.Millisecond)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		handledItemsMutex.Lock()
+		defer handledItemsMutex.Unlock()
+
+		assert.Equal(t, []int{1, 2, 3}, handledItems)
+	})
+}
+
+func TestPuller_WithHandlerContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ContextCancelledOnStopPull", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		started := make(chan struct{})
+		cancelled := make(chan struct{})
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandlerContext(func(ctx context.Context, item int) error {
+				close(started)
+				<-ctx.Done()
+				close(cancelled)
+
+				return ctx.Err()
+			}).
+			WithHandleAsynchronously()
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		err := puller.StopPull(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var abandonedItemsErr *AbandonedItemsError[int]
+		require.ErrorAs(t, err, &abandonedItemsErr)
+		assert.Equal(t, []int{1}, abandonedItemsErr.Items)
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			require.FailNow(t, "handler context was not cancelled")
+		}
+	})
+
+	t.Run("WithErrorHandler", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		handlerErr := errors.New("failed")
+		errorChan := make(chan error, 1)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandlerContext(func(ctx context.Context, item int) error {
+				return handlerErr
+			}).
+			WithErrorHandler(func(item int, err error) {
+				assert.Equal(t, 1, item)
+				errorChan <- err
+			})
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+
+		select {
+		case err := <-errorChan:
+			assert.ErrorIs(t, err, handlerErr)
+		case <-time.After(time.Second):
+			require.FailNow(t, "error handler was not called")
+		}
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("WithRetryPolicy", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		handledChan := make(chan int, 1)
+
+		var attempts int
+
+		var backoffRetries []int
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandlerContext(func(ctx context.Context, item int) error {
+				attempts++
+				if attempts < 3 {
+					return errors.New("failed")
+				}
+
+				handledChan <- item
+
+				return nil
+			}).
+			WithRetryPolicy(RetryPolicy{
+				MaxRetries: 3,
+				Backoff: func(retry int) time.Duration {
+					backoffRetries = append(backoffRetries, retry)
+					return time.Millisecond
+				},
+			}).
+			WithErrorHandler(func(item int, err error) {
+				assert.Fail(t, "error handler should not be called", err)
+			})
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+
+		select {
+		case item := <-handledChan:
+			assert.Equal(t, 1, item)
+		case <-time.After(time.Second):
+			require.FailNow(t, "item was not handled")
+		}
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []int{1, 2}, backoffRetries)
+	})
+
+	t.Run("ErrStopPulling", func(t *testing.T) {
+		t.Parallel()
+
+		for _, asynchronously := range []bool{false, true} {
+			itemChan := make(chan int, 10)
+			for i := 0; i < 10; i++ {
+				itemChan <- i
+			}
+
+			var handledItemsMutex sync.Mutex
+
+			handledItems := make([]int, 0)
+
+			puller := NewPuller[int]().
+				WithNotifyChannel(itemChan).
+				WithHandlerContext(func(ctx context.Context, item int) error {
+					handledItemsMutex.Lock()
+					defer handledItemsMutex.Unlock()
+
+					handledItems = append(handledItems, item)
+					if item == 0 {
+						return fmt.Errorf("item %d: %w", item, ErrStopPulling)
+					}
+
+					return nil
+				})
+			if asynchronously {
+				puller.WithHandleAsynchronouslyMaxGoroutine(1)
+			}
+
+			puller.StartPull(context.Background())
+
+			time.Sleep(time.Millisecond * 50)
+
+			err := puller.StopPull(context.Background())
+			require.NoError(t, err)
+
+			handledItemsMutex.Lock()
+			if asynchronously {
+				// the items pulled before the puller stops are still handled.
+				assert.Less(t, len(handledItems), 10)
+			} else {
+				assert.Equal(t, []int{0}, handledItems)
+			}
+			handledItemsMutex.Unlock()
+		}
+	})
+
+	t.Run("WithSourceHandlerContext", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		errorChan := make(chan error, 1)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan, WithSourceHandlerContext(func(ctx context.Context, item int) error {
+				return fmt.Errorf("failed to handle %d", item)
+			})).
+			WithErrorHandler(func(item int, err error) {
+				errorChan <- err
+			})
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+
+		select {
+		case err := <-errorChan:
+			assert.EqualError(t, err, "failed to handle 1")
+		case <-time.After(time.Second):
+			require.FailNow(t, "error handler was not called")
+		}
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestPuller_StopPull(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WaitForInFlightHandlers", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+
+		var handledCount atomic.Int64
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {
+				time.Sleep(time.Millisecond * 50)
+				handledCount.Add(1)
+			}).
+			WithHandleAsynchronouslyMaxGoroutine(5)
+		puller.StartPull(context.Background())
+
+		for i := 0; i < 5; i++ {
+			itemChan <- i
+		}
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(5), handledCount.Load())
+	})
+
+	t.Run("WithDrainOnStop", func(t *testing.T) {
+		t.Parallel()
+
+		for _, drainOnStop := range []bool{false, true} {
+			itemChan := make(chan int, 10)
+			started := make(chan struct{})
+			unblock := make(chan struct{})
+
+			var handledItemsMutex sync.Mutex
+
+			handledItems := make([]int, 0)
+
+			puller := NewPuller[int]().
+				WithNotifyChannel(itemChan).
+				WithHandler(func(item int) {
+					if item == 0 {
+						close(started)
+						<-unblock
+					}
+
+					handledItemsMutex.Lock()
+					defer handledItemsMutex.Unlock()
+
+					handledItems = append(handledItems, item)
+				})
+			if drainOnStop {
+				puller.WithDrainOnStop()
+			}
+
+			puller.StartPull(context.Background())
+
+			itemChan <- 0
+			<-started
+
+			for i := 1; i < 5; i++ {
+				itemChan <- i
+			}
+
+			time.AfterFunc(time.Millisecond*10, func() {
+				close(unblock)
+			})
+
+			err := puller.StopPull(context.Background())
+			require.NoError(t, err)
+
+			handledItemsMutex.Lock()
+			if drainOnStop {
+				assert.Equal(t, []int{0, 1, 2, 3, 4}, handledItems)
+				assert.Empty(t, itemChan)
+			} else {
+				assert.Equal(t, []int{0}, handledItems)
+				assert.Len(t, itemChan, 4)
+			}
+			handledItemsMutex.Unlock()
+		}
+	})
+
+	t.Run("AbandonedItems", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		unblock := make(chan struct{})
+
+		defer close(unblock)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {
+				<-unblock
+			}).
+			WithHandleAsynchronouslyMaxGoroutine(2)
+		puller.StartPull(context.Background())
+
+		for i := 0; i < 3; i++ {
+			itemChan <- i
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+
+		err := puller.StopPull(ctx)
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var abandonedItemsErr *AbandonedItemsError[int]
+		require.ErrorAs(t, err, &abandonedItemsErr)
+		assert.Equal(t, []int{0, 1, 2}, abandonedItemsErr.Items)
+		assert.Contains(t, err.Error(), "abandoned 3 in-flight items")
+	})
+}
+
+func TestPuller_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	waitForDone := func(t *testing.T, puller *Puller[int]) {
+		t.Helper()
+
+		select {
+		case <-puller.Done():
+		case <-time.After(time.Second):
+			require.FailNow(t, "puller did not stop")
+		}
+	}
+
+	t.Run("States", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		started := make(chan struct{})
+		unblock := make(chan struct{})
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {
+				close(started)
+				<-unblock
+			})
+		assert.Equal(t, PullerStateIdle, puller.State())
+
+		done := puller.Done()
+
+		puller.StartPull(context.Background())
+		assert.Equal(t, PullerStateRunning, puller.State())
+
+		itemChan <- 1
+		<-started
+
+		stopped := make(chan error)
+
+		go func() {
+			stopped <- puller.StopPull(context.Background())
+		}()
+
+		assert.Eventually(t, func() bool {
+			return puller.State() == PullerStateStopping
+		}, time.Second, time.Millisecond)
+		assert.NoError(t, puller.StopPull(context.Background()))
+
+		close(unblock)
+		require.NoError(t, <-stopped)
+
+		<-done
+		assert.Equal(t, PullerStateStopped, puller.State())
+		assert.Equal(t, "stopped", puller.State().String())
+		assert.NoError(t, puller.Err())
+	})
+
+	t.Run("SourcesClosed", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan1 := make(chan int)
+		itemChan2 := make(chan int)
+
+		var handledCount atomic.Int64
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan1).
+			WithNotifyChannel(itemChan2).
+			WithHandler(func(item int) {
+				handledCount.Add(1)
+			})
+		puller.StartPull(context.Background())
+
+		close(itemChan1)
+		itemChan2 <- 1
+
+		assert.Equal(t, PullerStateRunning, puller.State())
+
+		close(itemChan2)
+		waitForDone(t, puller)
+
+		assert.Equal(t, PullerStateStopped, puller.State())
+		assert.ErrorIs(t, puller.Err(), ErrSourcesClosed)
+		assert.Equal(t, int64(1), handledCount.Load())
+	})
+
+	t.Run("TickerClosed", func(t *testing.T) {
+		t.Parallel()
+
+		tickerChan := make(chan time.Time)
+		itemChan := make(chan int)
+
+		var pulledCount atomic.Int64
+
+		puller := NewPuller[int]().
+			WithTickerChannel(tickerChan, func(_ time.Time) int {
+				return int(pulledCount.Add(1))
+			}).
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {})
+		puller.StartPull(context.Background())
+
+		tickerChan <- time.Now()
+		close(tickerChan)
+
+		// the puller keeps running with the notify channel.
+		itemChan <- 1
+		assert.Equal(t, PullerStateRunning, puller.State())
+
+		close(itemChan)
+		waitForDone(t, puller)
+
+		assert.Equal(t, PullerStateStopped, puller.State())
+		assert.ErrorIs(t, puller.Err(), ErrSourcesClosed)
+		assert.Equal(t, int64(1), pulledCount.Load())
+	})
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(make(chan int)).
+			WithHandler(func(item int) {})
+		puller.StartPull(ctx)
+
+		cancel()
+		waitForDone(t, puller)
+
+		assert.Equal(t, PullerStateStopped, puller.State())
+		assert.ErrorIs(t, puller.Err(), context.Canceled)
+	})
+
+	t.Run("ErrStopPulling", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandlerContext(func(ctx context.Context, item int) error {
+				return fmt.Errorf("item %d: %w", item, ErrStopPulling)
+			})
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+		waitForDone(t, puller)
+
+		assert.Equal(t, PullerStateStopped, puller.State())
+		require.ErrorIs(t, puller.Err(), ErrStopPulling)
+		assert.EqualError(t, puller.Err(), "item 1: channelx: stop pulling")
+	})
+
+	t.Run("Abandoned", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		unblock := make(chan struct{})
+
+		defer close(unblock)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {
+				<-unblock
+			})
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+
+		err := puller.StopPull(ctx)
+		require.Error(t, err)
+
+		waitForDone(t, puller)
+		assert.Equal(t, PullerStateStopped, puller.State())
+		assert.Equal(t, err, puller.Err())
+	})
+
+	t.Run("Restart", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		handledChan := make(chan int)
+
+		var ticks atomic.Int64
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithTickerInterval(time.Millisecond, func(time.Time) int {
+				ticks.Add(1)
+				return -1
+			}).
+			WithHandler(func(item int) {
+				if item >= 0 {
+					handledChan <- item
+				}
+			})
+
+		for i := 0; i < 3; i++ {
+			puller.StartPull(context.Background())
+			done := puller.Done()
+
+			itemChan <- i
+			assert.Equal(t, i, <-handledChan)
+
+			ticksBefore := ticks.Load()
+			assert.Eventually(t, func() bool {
+				return ticks.Load() > ticksBefore
+			}, time.Second, time.Millisecond)
+
+			err := puller.StopPull(context.Background())
+			require.NoError(t, err)
+
+			<-done
+			assert.Equal(t, PullerStateStopped, puller.State())
+		}
+	})
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	backoff := ExponentialBackoff(time.Millisecond*10, time.Millisecond*50)
+
+	assert.Equal(t, time.Millisecond*10, backoff(1))
+	assert.Equal(t, time.Millisecond*20, backoff(2))
+	assert.Equal(t, time.Millisecond*40, backoff(3))
+	assert.Equal(t, time.Millisecond*50, backoff(4))
+	assert.Equal(t, time.Millisecond*50, backoff(100))
+	assert.Equal(t, time.Second, ConstantBackoff(time.Second)(10))
+}
+
+func TestPuller_WithRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RetryOnPanic", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		handledChan := make(chan int, 1)
+
+		var attempts atomic.Int64
+
+		var panicCount atomic.Int64
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {
+				if attempts.Add(1) < 3 {
+					panic("panic")
+				}
+
+				handledChan <- item
+			}).
+			WithPanicHandler(func(panicValue *panics.Recovered) {
+				panicCount.Add(1)
+			}).
+			WithRetryPolicy(RetryPolicy{MaxRetries: 2, Backoff: ConstantBackoff(time.Millisecond)})
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+		assert.Equal(t, 1, <-handledChan)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(3), attempts.Load())
+		assert.Equal(t, int64(2), panicCount.Load())
+	})
+
+	t.Run("WithDeadLetterHandler", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		handlerErr := errors.New("failed")
+		deadLetterChan := make(chan DeadLetter[int], 1)
+		errorChan := make(chan error, 1)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandlerContext(func(ctx context.Context, item int) error {
+				return handlerErr
+			}).
+			WithRetryPolicy(RetryPolicy{MaxRetries: 2}).
+			WithErrorHandler(func(item int, err error) {
+				errorChan <- err
+			}).
+			WithDeadLetterHandler(func(letter DeadLetter[int]) {
+				deadLetterChan <- letter
+			})
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+
+		letter := <-deadLetterChan
+		assert.Equal(t, 1, letter.Item)
+		assert.Equal(t, 3, letter.Attempts)
+		require.ErrorIs(t, letter.Err, handlerErr)
+		require.ErrorIs(t, <-errorChan, handlerErr)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("WithDeadLetterChannel", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		deadLetterChan := make(chan DeadLetter[int])
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandler(func(item int) {
+				panic(errors.New("panic error"))
+			}).
+			WithDeadLetterChannel(deadLetterChan)
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+
+		letter := <-deadLetterChan
+		assert.Equal(t, 1, letter.Item)
+		assert.Equal(t, 1, letter.Attempts)
+
+		var recovered *panics.ErrRecovered
+		require.ErrorAs(t, letter.Err, &recovered)
+		assert.EqualError(t, errors.Unwrap(letter.Err), "panic error")
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("WithDeadLetterChannelUnread", func(t *testing.T) {
+		t.Parallel()
+
+		for _, asynchronously := range []bool{false, true} {
+			itemChan := make(chan int)
+			handled := make(chan struct{})
+
+			puller := NewPuller[int]().
+				WithNotifyChannel(itemChan).
+				WithHandlerContext(func(ctx context.Context, item int) error {
+					close(handled)
+					return errors.New("failed")
+				}).
+				WithDeadLetterChannel(make(chan DeadLetter[int]))
+			if asynchronously {
+				puller = puller.WithHandleAsynchronously()
+			}
+
+			puller.StartPull(context.Background())
+
+			itemChan <- 1
+			<-handled
+
+			stopped := make(chan error, 1)
+			go func() {
+				stopped <- puller.StopPull(context.Background())
+			}()
+
+			select {
+			case err := <-stopped:
+				require.NoError(t, err)
+			case <-time.After(time.Second):
+				require.FailNow(t, "StopPull blocked by the unread dead letter channel")
+			}
+		}
+	})
+
+	t.Run("WithDeadLetterChannelUnreadSourcesClosed", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandleAsynchronously().
+			WithHandlerContext(func(ctx context.Context, item int) error {
+				return errors.New("failed")
+			}).
+			WithDeadLetterChannel(make(chan DeadLetter[int]))
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+		close(itemChan)
+
+		select {
+		case <-puller.Done():
+		case <-time.After(time.Second):
+			require.FailNow(t, "puller blocked by the unread dead letter channel")
+		}
+
+		assert.ErrorIs(t, puller.Err(), ErrSourcesClosed)
+	})
+
+	t.Run("RetryIf", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		deadLetterChan := make(chan DeadLetter[int], 1)
+		permanentErr := errors.New("permanent")
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandlerContext(func(ctx context.Context, item int) error {
+				return permanentErr
+			}).
+			WithRetryPolicy(RetryPolicy{
+				MaxRetries: 5,
+				RetryIf: func(err error) bool {
+					return !errors.Is(err, permanentErr)
+				},
+			}).
+			WithDeadLetterChannel(deadLetterChan)
+		puller.StartPull(context.Background())
+
+		itemChan <- 1
+
+		letter := <-deadLetterChan
+		assert.Equal(t, 1, letter.Attempts)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("WithHandlerTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		itemChan := make(chan int)
+		deadLetterChan := make(chan DeadLetter[int], 1)
+
+		puller := NewPuller[int]().
+			WithNotifyChannel(itemChan).
+			WithHandlerContext(func(ctx context.Context, item int) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}).
+			WithHandlerTimeout(time.Millisecond * 10).
+			WithRetryPolicy(RetryPolicy{MaxRetries: 1}).
+			WithDeadLetterChannel(deadLetterChan)
+		puller.StartPull(context.Background())
+
+		now := time.Now()
+		itemChan <- 1
+
+		letter := <-deadLetterChan
+		assert.Equal(t, 2, letter.Attempts)
+		require.ErrorIs(t, letter.Err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(now), time.Second)
+
+		err := puller.StopPull(context.Background())
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,324 @@
+// Package stream provides the combinators of channels. The output channels of the
+// combinators are closed once the input channels are closed or the ctx is done, the
+// goroutines of the combinators exit by then, even if nobody reads the output channels.
+package stream
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// send sends v to ch, it returns false if ctx is done before v is sent.
+func send[T any](ctx context.Context, ch chan<- T, v T) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case ch <- v:
+		return true
+	}
+}
+
+// receive receives from src, ok is false if src is closed or ctx is done.
+func receive[T any](ctx context.Context, src <-chan T) (v T, ok bool) {
+	select {
+	case <-ctx.Done():
+		return v, false
+	case v, ok = <-src:
+		return v, ok
+	}
+}
+
+func makeChannels[T any](n int) ([]chan T, []<-chan T) {
+	channels := make([]chan T, n)
+	outputs := make([]<-chan T, n)
+
+	for i := range channels {
+		channels[i] = make(chan T)
+		outputs[i] = channels[i]
+	}
+
+	return channels, outputs
+}
+
+func closeChannels[T any](channels []chan T) {
+	for _, ch := range channels {
+		close(ch)
+	}
+}
+
+// Tee duplicates every item of src to n output channels. The items are sent to the outputs
+// in the order they get ready to receive, so that a slow consumer doesn't hold the other
+// consumers from receiving the current item, the next item is pulled from src once all of
+// the outputs have received the current one. Use Buffer to decouple the slow consumers.
+func Tee[T any](ctx context.Context, src <-chan T, n int) []<-chan T {
+	channels, outputs := makeChannels[T](n)
+
+	go func() {
+		defer closeChannels(channels)
+
+		for {
+			v, ok := receive(ctx, src)
+			if !ok {
+				return
+			}
+
+			cases := make([]reflect.SelectCase, 0, n+1)
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+
+			for _, ch := range channels {
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(ch), Send: reflect.ValueOf(&v).Elem()})
+			}
+
+			for len(cases) > 1 {
+				chosen, _, _ := reflect.Select(cases)
+				if chosen == 0 {
+					return
+				}
+
+				cases = append(cases[:chosen], cases[chosen+1:]...)
+			}
+		}
+	}()
+
+	return outputs
+}
+
+// FanOut distributes the items of src to n output channels, every item is sent to exactly
+// one of the outputs which is ready to receive. It panics if n is less than 1, since there
+// is no output to receive the items.
+func FanOut[T any](ctx context.Context, src <-chan T, n int) []<-chan T {
+	if n < 1 {
+		panic("stream: number of outputs must be greater than zero")
+	}
+
+	channels, outputs := makeChannels[T](n)
+
+	go func() {
+		defer closeChannels(channels)
+
+		cases := make([]reflect.SelectCase, 0, n+1)
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+
+		for _, ch := range channels {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(ch)})
+		}
+
+		for {
+			v, ok := receive(ctx, src)
+			if !ok {
+				return
+			}
+
+			for i := 1; i < len(cases); i++ {
+				cases[i].Send = reflect.ValueOf(&v).Elem()
+			}
+
+			chosen, _, _ := reflect.Select(cases)
+			if chosen == 0 {
+				return
+			}
+		}
+	}()
+
+	return outputs
+}
+
+// Merge merges the items of srcs into a single output channel, the output is closed once
+// all of srcs are closed.
+func Merge[T any](ctx context.Context, srcs ...<-chan T) <-chan T {
+	dst := make(chan T)
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(srcs))
+
+	for _, src := range srcs {
+		go func(src <-chan T) {
+			defer wg.Done()
+
+			for {
+				v, ok := receive(ctx, src)
+				if !ok || !send(ctx, dst, v) {
+					return
+				}
+			}
+		}(src)
+	}
+
+	go func() {
+		wg.Wait()
+		close(dst)
+	}()
+
+	return dst
+}
+
+// FanIn is an alias of Merge.
+func FanIn[T any](ctx context.Context, srcs ...<-chan T) <-chan T {
+	return Merge(ctx, srcs...)
+}
+
+// Map maps the items of src by fn.
+func Map[T any, R any](ctx context.Context, src <-chan T, fn func(T) R) <-chan R {
+	dst := make(chan R)
+
+	go func() {
+		defer close(dst)
+
+		for {
+			v, ok := receive(ctx, src)
+			if !ok || !send(ctx, dst, fn(v)) {
+				return
+			}
+		}
+	}()
+
+	return dst
+}
+
+// Filter passes the items of src that fn returns true for.
+func Filter[T any](ctx context.Context, src <-chan T, fn func(T) bool) <-chan T {
+	dst := make(chan T)
+
+	go func() {
+		defer close(dst)
+
+		for {
+			v, ok := receive(ctx, src)
+			if !ok {
+				return
+			}
+			if fn(v) && !send(ctx, dst, v) {
+				return
+			}
+		}
+	}()
+
+	return dst
+}
+
+// Batch collects the items of src into batches, a batch is sent once it reaches size items,
+// or maxWait elapses since the first item of the batch is received, the batches are only sent
+// by size if maxWait is zero. The pending batch is sent when src is closed, and dropped when
+// ctx is done. It panics if size is less than 1.
+func Batch[T any](ctx context.Context, src <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size < 1 {
+		panic("stream: batch size must be greater than zero")
+	}
+
+	dst := make(chan []T)
+
+	go func() {
+		defer close(dst)
+
+		var batch []T
+
+		var timer *time.Timer
+
+		var timeout <-chan time.Time
+
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+
+			items := batch
+			batch = nil
+
+			return send(ctx, dst, items)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timeout:
+				if !flush() {
+					return
+				}
+			case v, ok := <-src:
+				if !ok {
+					if len(batch) > 0 {
+						flush()
+					}
+
+					return
+				}
+
+				batch = append(batch, v)
+				if len(batch) >= size {
+					if !flush() {
+						return
+					}
+
+					continue
+				}
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timeout = timer.C
+				}
+			}
+		}
+	}()
+
+	return dst
+}
+
+// Throttle limits the rate of the items of src to at most one item per interval, the items
+// are delayed rather than dropped.
+func Throttle[T any](ctx context.Context, src <-chan T, interval time.Duration) <-chan T {
+	dst := make(chan T)
+
+	go func() {
+		defer close(dst)
+
+		var last time.Time
+
+		for {
+			v, ok := receive(ctx, src)
+			if !ok {
+				return
+			}
+
+			if wait := time.Until(last.Add(interval)); wait > 0 {
+				timer := time.NewTimer(wait)
+
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+			if !send(ctx, dst, v) {
+				return
+			}
+
+			last = time.Now()
+		}
+	}()
+
+	return dst
+}
+
+// Buffer buffers up to size items of src, so that a slow consumer doesn't block the producer
+// until the buffer is full.
+func Buffer[T any](ctx context.Context, src <-chan T, size int) <-chan T {
+	dst := make(chan T, size)
+
+	go func() {
+		defer close(dst)
+
+		for {
+			v, ok := receive(ctx, src)
+			if !ok || !send(ctx, dst, v) {
+				return
+			}
+		}
+	}()
+
+	return dst
+}
@@ -0,0 +1,409 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func generate(ctx context.Context, items ...int) <-chan int {
+	src := make(chan int)
+
+	go func() {
+		defer close(src)
+
+		for _, item := range items {
+			if !send(ctx, src, item) {
+				return
+			}
+		}
+	}()
+
+	return src
+}
+
+var errFailed = errors.New("failed")
+
+// generateErrors returns the closed channel buffering errs, which may be nil.
+func generateErrors(errs ...error) <-chan error {
+	src := make(chan error, len(errs))
+	for _, err := range errs {
+		src <- err
+	}
+
+	close(src)
+
+	return src
+}
+
+func collect[T any](t *testing.T, src <-chan T) []T {
+	t.Helper()
+
+	items := make([]T, 0)
+
+	timeout := time.After(time.Second)
+
+	for {
+		select {
+		case item, ok := <-src:
+			if !ok {
+				return items
+			}
+
+			items = append(items, item)
+		case <-timeout:
+			assert.Fail(t, "channel not closed")
+			return items
+		}
+	}
+}
+
+func assertClosed[T any](t *testing.T, src <-chan T) {
+	t.Helper()
+
+	select {
+	case _, ok := <-src:
+		for ok {
+			_, ok = <-src
+		}
+	case <-time.After(time.Second):
+		require.FailNow(t, "channel not closed")
+	}
+}
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+
+		outputs := Tee(context.Background(), generate(context.Background(), 1, 2, 3), 3)
+		require.Len(t, outputs, 3)
+
+		results := make([][]int, len(outputs))
+
+		var wg sync.WaitGroup
+
+		for i, output := range outputs {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				results[i] = collect(t, output)
+			}()
+		}
+
+		wg.Wait()
+
+		for _, result := range results {
+			assert.Equal(t, []int{1, 2, 3}, result)
+		}
+	})
+
+	t.Run("NilInterface", func(t *testing.T) {
+		t.Parallel()
+
+		outputs := Tee(context.Background(), generateErrors(nil, errFailed), 2)
+
+		var wg sync.WaitGroup
+
+		for _, output := range outputs {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				assert.Equal(t, []error{nil, errFailed}, collect(t, output))
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("SlowConsumer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		outputs := Tee(ctx, generate(ctx, 1, 2), 2)
+
+		// the item is received by the second output while nobody reads the first output.
+		select {
+		case item := <-outputs[1]:
+			assert.Equal(t, 1, item)
+		case <-time.After(time.Second):
+			require.FailNow(t, "blocked by the slow consumer")
+		}
+
+		assert.Equal(t, 1, <-outputs[0])
+		assert.Equal(t, 2, <-outputs[0])
+		assert.Equal(t, 2, <-outputs[1])
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		outputs := Tee(ctx, make(chan int), 2)
+
+		cancel()
+
+		for _, output := range outputs {
+			assertClosed(t, output)
+		}
+	})
+}
+
+func TestFanOut(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NilInterface", func(t *testing.T) {
+		t.Parallel()
+
+		outputs := FanOut(context.Background(), generateErrors(nil, errFailed), 1)
+		require.Len(t, outputs, 1)
+		assert.Equal(t, []error{nil, errFailed}, collect(t, outputs[0]))
+	})
+
+	t.Run("InvalidN", func(t *testing.T) {
+		t.Parallel()
+
+		for _, n := range []int{0, -1} {
+			assert.Panics(t, func() {
+				FanOut(context.Background(), make(chan int), n)
+			})
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+
+		outputs := FanOut(context.Background(), generate(context.Background(), 1, 2, 3, 4, 5, 6), 3)
+
+		var mutex sync.Mutex
+
+		results := make([]int, 0)
+
+		var wg sync.WaitGroup
+
+		for _, output := range outputs {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				items := collect(t, output)
+
+				mutex.Lock()
+				results = append(results, items...)
+				mutex.Unlock()
+			}()
+		}
+
+		wg.Wait()
+
+		sort.Ints(results)
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, results)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		outputs := FanOut(ctx, generate(ctx, 1, 2, 3), 2)
+
+		// nobody reads the outputs.
+		cancel()
+
+		for _, output := range outputs {
+			assertClosed(t, output)
+		}
+	})
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		results := collect(t, Merge(ctx, generate(ctx, 1, 2), generate(ctx, 3), generate(ctx)))
+
+		sort.Ints(results)
+		assert.Equal(t, []int{1, 2, 3}, results)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, collect(t, FanIn[int](context.Background())))
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		output := Merge(ctx, make(chan int), generate(ctx, 1, 2, 3))
+
+		assert.Equal(t, 1, <-output)
+		cancel()
+		assertClosed(t, output)
+	})
+}
+
+func TestMapAndFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		src := generate(ctx, 1, 2, 3, 4, 5)
+
+		odd := Filter(ctx, src, func(v int) bool {
+			return v%2 == 1
+		})
+		letters := Map(ctx, odd, func(v int) string {
+			return string(rune('a' + v))
+		})
+
+		assert.Equal(t, []string{"b", "d", "f"}, collect(t, letters))
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		src := generate(ctx, 1, 2, 3)
+
+		mapped := Map(ctx, Filter(ctx, src, func(int) bool { return true }), func(v int) int { return v })
+
+		assert.Equal(t, 1, <-mapped)
+		cancel()
+		assertClosed(t, mapped)
+	})
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("BySize", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		batches := collect(t, Batch(ctx, generate(ctx, 1, 2, 3, 4, 5), 2, 0))
+
+		assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, batches)
+	})
+
+	t.Run("ByMaxWait", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		src := make(chan int)
+		batches := Batch(ctx, src, 10, time.Millisecond*10)
+
+		src <- 1
+		src <- 2
+		assert.Equal(t, []int{1, 2}, <-batches)
+
+		src <- 3
+		close(src)
+		assert.Equal(t, [][]int{{3}}, collect(t, batches))
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		src := make(chan int)
+		batches := Batch(ctx, src, 10, time.Hour)
+
+		src <- 1
+		cancel()
+		assertClosed(t, batches)
+	})
+
+	t.Run("InvalidSize", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() {
+			Batch(context.Background(), make(chan int), 0, 0)
+		})
+	})
+}
+
+func TestThrottle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		interval := time.Millisecond * 20
+
+		startedAt := time.Now()
+		results := collect(t, Throttle(ctx, generate(ctx, 1, 2, 3, 4), interval))
+
+		assert.Equal(t, []int{1, 2, 3, 4}, results)
+		assert.GreaterOrEqual(t, time.Since(startedAt), 3*interval)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		output := Throttle(ctx, generate(ctx, 1, 2, 3), time.Hour)
+
+		assert.Equal(t, 1, <-output)
+		cancel()
+		assertClosed(t, output)
+	})
+}
+
+func TestBuffer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+
+		src := make(chan int)
+		output := Buffer(context.Background(), src, 3)
+
+		// the producer is not blocked until the buffer is full.
+		for i := 1; i <= 3; i++ {
+			select {
+			case src <- i:
+			case <-time.After(time.Second):
+				require.FailNow(t, "blocked by the consumer")
+			}
+		}
+
+		close(src)
+		assert.Equal(t, []int{1, 2, 3}, collect(t, output))
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		output := Buffer(ctx, generate(ctx, 1, 2, 3, 4, 5), 1)
+
+		cancel()
+		assertClosed(t, output)
+	})
+}
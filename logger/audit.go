@@ -0,0 +1,293 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	auditSeqKey      = "audit_seq"
+	auditPrevHashKey = "audit_prev_hash"
+	auditHashKey     = "audit_hash"
+)
+
+var auditHashSuffixPrefix = []byte(`,"` + auditHashKey + `":"`)
+
+// ErrAuditLoggerNotConfigured is returned when writing to the audit logger of a
+// Logger created without WithAuditLogFilePath.
+var ErrAuditLoggerNotConfigured = errors.New("audit logger is not configured, use WithAuditLogFilePath to configure it")
+
+// AuditChainBrokenError reports the first entry of an audit log that breaks the
+// hash chain, either because it was altered, removed, reordered or inserted.
+type AuditChainBrokenError struct {
+	// Line is the 1-based line number of the offending entry.
+	Line int
+	// Reason describes why the entry is considered broken.
+	Reason string
+}
+
+func (e *AuditChainBrokenError) Error() string {
+	return fmt.Sprintf("audit log chain broken at line %d: %s", e.Line, e.Reason)
+}
+
+// AuditLogger writes append-only JSON lines to a dedicated audit log file. Each
+// entry carries a sequence number, the hash of the previous entry and its own
+// SHA-256 hash, so that any alteration of the file can be detected with
+// VerifyAuditLog or VerifyAuditLogFile.
+type AuditLogger struct {
+	mutex    sync.Mutex
+	file     *os.File
+	encoder  zapcore.Encoder
+	seq      uint64
+	prevHash string
+}
+
+// NewAuditLogger creates an audit logger writing to logFilePath. The file and its
+// directory are created when missing, and the hash chain is resumed from the last
+// entry when the file already contains entries. WithAppName, WithNamespace and
+// WithInitialFields are honored, other options are ignored.
+func NewAuditLogger(logFilePath string, callOpts ...NewLoggerCallOption) (*AuditLogger, error) {
+	opts := new(newLoggerOptions)
+	for _, opt := range callOpts {
+		opt(opts)
+	}
+
+	return newAuditLogger(logFilePath, opts)
+}
+
+func newAuditLogger(logFilePath string, opts *newLoggerOptions) (*AuditLogger, error) {
+	err := autoCreateLogFile(logFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &AuditLogger{
+		encoder: zapcore.NewJSONEncoder(newEncoderConfig()),
+	}
+
+	if opts.appName != "" {
+		a.encoder.AddString("app_name", opts.appName)
+	}
+	if opts.namespace != "" {
+		a.encoder.AddString("namespace", opts.namespace)
+	}
+
+	for k, v := range opts.initialFields {
+		zap.Any(k, v).AddTo(a.encoder)
+	}
+
+	err = a.resume(logFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	a.file, err = os.OpenFile(logFilePath, os.O_APPEND|os.O_WRONLY, 0) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s audit log file: %w", logFilePath, err)
+	}
+
+	return a, nil
+}
+
+func (a *AuditLogger) resume(logFilePath string) error {
+	file, err := os.Open(logFilePath) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("failed to open %s audit log file: %w", logFilePath, err)
+	}
+
+	defer file.Close()
+
+	scanner := newAuditLogScanner(file)
+
+	var last []byte
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) > 0 {
+			last = append(last[:0], scanner.Bytes()...)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return fmt.Errorf("failed to read %s audit log file: %w", logFilePath, err)
+	}
+	if last == nil {
+		return nil
+	}
+
+	entry, hash, reason := parseAuditLogLine(last)
+	if reason != "" {
+		return fmt.Errorf("failed to resume %s audit log file: %s", logFilePath, reason)
+	}
+
+	a.seq = entry.Seq
+	a.prevHash = hash
+
+	return nil
+}
+
+// Log writes a single audit entry. The entry is flushed to disk before Log returns.
+func (a *AuditLogger) Log(msg string, fields ...zap.Field) error {
+	if a == nil {
+		return ErrAuditLoggerNotConfigured
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	fields = append(fields[:len(fields):len(fields)],
+		zap.Uint64(auditSeqKey, a.seq+1),
+		zap.String(auditPrevHashKey, a.prevHash),
+	)
+
+	buffer, err := a.encoder.EncodeEntry(zapcore.Entry{
+		Level:   zapcore.InfoLevel,
+		Time:    time.Now(),
+		Message: msg,
+	}, fields)
+	if err != nil {
+		return err
+	}
+
+	defer buffer.Free()
+
+	content := bytes.TrimRight(buffer.Bytes(), "\r\n")
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	line := make([]byte, 0, len(content)+len(auditHashSuffixPrefix)+len(hash)+3)
+	line = append(line, content[:len(content)-1]...)
+	line = append(line, auditHashSuffixPrefix...)
+	line = append(line, hash...)
+	line = append(line, "\"}\n"...)
+
+	_, err = a.file.Write(line)
+	if err != nil {
+		return err
+	}
+
+	err = a.file.Sync()
+	if err != nil {
+		return err
+	}
+
+	a.seq++
+	a.prevHash = hash
+
+	return nil
+}
+
+// Close closes the underlying audit log file.
+func (a *AuditLogger) Close() error {
+	if a == nil {
+		return nil
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.file.Close()
+}
+
+// VerifyAuditLogFile verifies the hash chain of the audit log file at logFilePath,
+// see VerifyAuditLog for details.
+func VerifyAuditLogFile(logFilePath string) error {
+	file, err := os.Open(logFilePath) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return VerifyAuditLog(file)
+}
+
+// VerifyAuditLog walks the audit log entries read from r and verifies the hash
+// of every entry as well as the link to its predecessor. It returns an
+// *AuditChainBrokenError describing the first broken link, nil when the chain is
+// intact, or the underlying error if r fails to be read.
+func VerifyAuditLog(r io.Reader) error {
+	scanner := newAuditLogScanner(r)
+
+	var (
+		lineNumber int
+		prevSeq    uint64
+		prevHash   string
+	)
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		entry, hash, reason := parseAuditLogLine(line)
+		if reason != "" {
+			return &AuditChainBrokenError{Line: lineNumber, Reason: reason}
+		}
+		if entry.Seq != prevSeq+1 {
+			return &AuditChainBrokenError{Line: lineNumber, Reason: fmt.Sprintf("expected sequence %d, got %d", prevSeq+1, entry.Seq)}
+		}
+		if entry.PrevHash != prevHash {
+			return &AuditChainBrokenError{Line: lineNumber, Reason: "previous hash does not match the hash of the previous entry"}
+		}
+
+		prevSeq = entry.Seq
+		prevHash = hash
+	}
+
+	return scanner.Err()
+}
+
+type auditLogEntry struct {
+	Seq      uint64 `json:"audit_seq"`
+	PrevHash string `json:"audit_prev_hash"`
+}
+
+// parseAuditLogLine splits the trailing hash from the line, verifies it against the
+// rest of the entry and decodes the chaining fields. A non-empty reason is returned
+// when the line is malformed or its hash does not match.
+func parseAuditLogLine(line []byte) (entry auditLogEntry, hash string, reason string) {
+	index := bytes.LastIndex(line, auditHashSuffixPrefix)
+	if index < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return entry, "", "missing " + auditHashKey
+	}
+
+	hash = string(line[index+len(auditHashSuffixPrefix) : len(line)-2])
+
+	content := make([]byte, 0, index+1)
+	content = append(content, line[:index]...)
+	content = append(content, '}')
+
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != hash {
+		return entry, "", "entry hash mismatch, the entry has been altered"
+	}
+
+	err := json.Unmarshal(content, &entry)
+	if err != nil {
+		return entry, "", "malformed entry: " + err.Error()
+	}
+
+	return entry, hash, ""
+}
+
+func newAuditLogScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	return scanner
+}
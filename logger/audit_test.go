@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuditLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Verify", func(t *testing.T) {
+		t.Parallel()
+
+		logFilePath := filepath.Join(t.TempDir(), "logs", "audit.log")
+
+		logger, err := NewLogger(
+			WithAppName("xo"),
+			WithAuditLogFilePath(logFilePath),
+		)
+		require.NoError(t, err)
+		require.NotNil(t, logger.Audit())
+
+		require.NoError(t, logger.Audit().Log("user created", zap.String("user_id", "1")))
+		require.NoError(t, logger.Audit().Log("user deleted", zap.String("user_id", "1")))
+		require.NoError(t, logger.Audit().Close())
+
+		require.NoError(t, VerifyAuditLogFile(logFilePath))
+
+		content, err := os.ReadFile(logFilePath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"app_name":"xo"`)
+		assert.Equal(t, 2, bytes.Count(content, []byte("\n")))
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		t.Parallel()
+
+		logFilePath := filepath.Join(t.TempDir(), "audit.log")
+
+		audit, err := NewAuditLogger(logFilePath)
+		require.NoError(t, err)
+		require.NoError(t, audit.Log("first"))
+		require.NoError(t, audit.Close())
+
+		audit, err = NewAuditLogger(logFilePath)
+		require.NoError(t, err)
+		require.NoError(t, audit.Log("second"))
+		require.NoError(t, audit.Close())
+
+		require.NoError(t, VerifyAuditLogFile(logFilePath))
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		t.Parallel()
+
+		logFilePath := filepath.Join(t.TempDir(), "audit.log")
+
+		audit, err := NewAuditLogger(logFilePath)
+		require.NoError(t, err)
+
+		for _, msg := range []string{"first", "second", "third"} {
+			require.NoError(t, audit.Log(msg, zap.Int("amount", 100)))
+		}
+
+		require.NoError(t, audit.Close())
+
+		content, err := os.ReadFile(logFilePath)
+		require.NoError(t, err)
+
+		lines := bytes.SplitAfter(content, []byte("\n"))
+
+		altered := bytes.Join(lines, nil)
+		altered = bytes.Replace(altered, []byte(`"message":"second","amount":100`), []byte(`"message":"second","amount":1`), 1)
+
+		var brokenErr *AuditChainBrokenError
+
+		err = VerifyAuditLog(bytes.NewReader(altered))
+		require.ErrorAs(t, err, &brokenErr)
+		assert.Equal(t, 2, brokenErr.Line)
+
+		removed := bytes.Join([][]byte{lines[0], lines[2]}, nil)
+
+		err = VerifyAuditLog(bytes.NewReader(removed))
+		require.ErrorAs(t, err, &brokenErr)
+		assert.Equal(t, 2, brokenErr.Line)
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		t.Parallel()
+
+		logger, err := NewLogger()
+		require.NoError(t, err)
+		require.ErrorIs(t, logger.Audit().Log("message"), ErrAuditLoggerNotConfigured)
+	})
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/nekomeowww/fo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// flushTimeout restricts how long Fatal and Panic wait for the sinks to be flushed.
+const flushTimeout = 5 * time.Second
+
+// exit is replaced in tests to observe the exit code instead of exiting.
+var exit = os.Exit
+
+type flushThenExitHook struct {
+	logger *Logger
+}
+
+func (h flushThenExitHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	h.logger.flushWithTimeout()
+	exit(1)
+}
+
+type flushThenPanicHook struct {
+	logger *Logger
+}
+
+func (h flushThenPanicHook) OnWrite(ce *zapcore.CheckedEntry, _ []zapcore.Field) {
+	h.logger.flushWithTimeout()
+	panic(ce.Message)
+}
+
+func (l *Logger) flushWithTimeout() {
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	_ = l.Flush(ctx)
+}
+
+// Flush synchronously flushes every sink of the logger, including the zap outputs,
+// the loki pusher, the tracer provider configured by WithTracerProvider, or the global
+// OpenTelemetry tracer provider if not configured, when OpenTelemetry is enabled, and
+// the meter provider configured by WithMetrics. You may pass a context to restrict
+// the deadline of the flush.
+func (l *Logger) Flush(ctx context.Context) error {
+	return fo.Invoke0(ctx, func() error {
+		var errs []error
+
+		err := l.ZapLogger.Sync()
+		if err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTTY) {
+			errs = append(errs, err)
+		}
+
+		if !l.openTelemetryDisabled {
+			tracerProvider := l.tracerProvider
+			if tracerProvider == nil {
+				tracerProvider = otel.GetTracerProvider()
+			}
+
+			flusher, ok := tracerProvider.(interface {
+				ForceFlush(ctx context.Context) error
+			})
+			if ok {
+				errs = append(errs, flusher.ForceFlush(ctx))
+			}
+		}
+
+		if l.meterProvider != nil {
+			flusher, ok := l.meterProvider.(interface {
+				ForceFlush(ctx context.Context) error
+			})
+			if ok {
+				errs = append(errs, flusher.ForceFlush(ctx))
+			}
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+// Close flushes the logger with Flush, then closes the connections held by the logger,
+// such as the ones to the syslog server and systemd-journald configured by WithSyslogConfig
+// and WithJournaldConfig. The loggers derived by With share the connections, they must
+// not be used after Close.
+func (l *Logger) Close(ctx context.Context) error {
+	errs := []error{l.Flush(ctx)}
+
+	for _, closer := range l.closers {
+		errs = append(errs, closer.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// RecoverAndLog recovers from a panic and logs the recovered value with the stack
+// trace at ErrorLevel. The exception is recorded on the span of ctx as well. It
+// must be called directly with defer:
+//
+//	defer logger.RecoverAndLog(ctx)
+func (l *Logger) RecoverAndLog(ctx context.Context) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+
+	if !l.openTelemetryDisabled {
+		l.recordException(ctx, err, false)
+	}
+
+	l.Error("recovered from panic", zap.Error(err), zap.StackSkip("stack", 2))
+}
+
+// Go runs fn in a new goroutine, panics raised by fn are recovered and logged
+// with RecoverAndLog instead of crashing the program.
+func (l *Logger) Go(ctx context.Context, fn func(ctx context.Context)) {
+	go func() {
+		defer l.RecoverAndLog(ctx)
+
+		fn(ctx)
+	}()
+}
+
+func (l *Logger) recordException(ctx context.Context, err error, endSpan bool) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	span.RecordError(err, trace.WithStackTrace(true))
+	span.SetStatus(codes.Error, err.Error())
+
+	if endSpan {
+		span.End()
+	}
+}
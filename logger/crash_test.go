@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func newTestTracerProvider() (*trace.TracerProvider, *tracetest.SpanRecorder) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := trace.NewTracerProvider(
+		trace.WithSpanProcessor(spanRecorder),
+		trace.WithSampler(trace.AlwaysSample()),
+	)
+
+	return tracerProvider, spanRecorder
+}
+
+func readLogFile(t *testing.T, logFilePath string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	return string(content)
+}
+
+func TestRecoverAndLog(t *testing.T) {
+	t.Parallel()
+
+	logFilePath := filepath.Join(t.TempDir(), "recover.log")
+
+	logger, err := NewLogger(WithFormat(FormatJSON), WithLogFilePath(logFilePath))
+	require.NoError(t, err)
+
+	tracerProvider, spanRecorder := newTestTracerProvider()
+	ctx, span := tracerProvider.Tracer("test").Start(context.Background(), "test-span")
+
+	func() {
+		defer logger.RecoverAndLog(ctx)
+
+		panic("boom")
+	}()
+
+	span.End()
+
+	content := readLogFile(t, logFilePath)
+	assert.Contains(t, content, "recovered from panic")
+	assert.Contains(t, content, "boom")
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	require.NotEmpty(t, spans[0].Events())
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
+
+func TestGo(t *testing.T) {
+	t.Parallel()
+
+	logFilePath := filepath.Join(t.TempDir(), "go.log")
+
+	logger, err := NewLogger(WithFormat(FormatJSON), WithLogFilePath(logFilePath))
+	require.NoError(t, err)
+
+	logger.Go(context.Background(), func(ctx context.Context) {
+		panic("boom in goroutine")
+	})
+
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(logFilePath)
+		return err == nil && strings.Contains(string(content), "boom in goroutine")
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestFlush(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := trace.NewTracerProvider(
+		trace.WithBatcher(exporter, trace.WithBatchTimeout(time.Hour)),
+		trace.WithSampler(trace.AlwaysSample()),
+	)
+
+	logger, err := NewLogger(
+		WithFormat(FormatJSON),
+		WithLogFilePath(filepath.Join(t.TempDir(), "flush.log")),
+		WithTracerProvider(tracerProvider),
+	)
+	require.NoError(t, err)
+
+	_, spanLogger := logger.StartSpan(context.Background(), "handle")
+	spanLogger.End()
+	assert.Empty(t, exporter.GetSpans())
+
+	require.NoError(t, logger.Flush(context.Background()))
+	assert.Len(t, exporter.GetSpans(), 1)
+}
+
+func TestPanic(t *testing.T) {
+	t.Parallel()
+
+	logFilePath := filepath.Join(t.TempDir(), "panic.log")
+
+	logger, err := NewLogger(WithFormat(FormatJSON), WithLogFilePath(logFilePath))
+	require.NoError(t, err)
+
+	tracerProvider, spanRecorder := newTestTracerProvider()
+	ctx, span := tracerProvider.Tracer("test").Start(context.Background(), "test-span")
+
+	require.PanicsWithValue(t, "panic message", func() {
+		logger.PanicContext(ctx, "panic message", zap.String("key", "value"))
+	})
+
+	span.End()
+
+	content := readLogFile(t, logFilePath)
+	assert.Contains(t, content, `"level":"panic"`)
+	assert.Contains(t, content, `"key":"value"`)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestFatal(t *testing.T) {
+	exitCode := -1
+	exit = func(code int) {
+		exitCode = code
+	}
+
+	defer func() {
+		exit = os.Exit
+	}()
+
+	logFilePath := filepath.Join(t.TempDir(), "fatal.log")
+
+	logger, err := NewLogger(WithFormat(FormatJSON), WithLogFilePath(logFilePath))
+	require.NoError(t, err)
+
+	tracerProvider, spanRecorder := newTestTracerProvider()
+	ctx, _ := tracerProvider.Tracer("test").Start(context.Background(), "test-span")
+
+	logger.FatalContext(ctx, "fatal message", zap.String("key", "value"))
+
+	assert.Equal(t, 1, exitCode)
+
+	content := readLogFile(t, logFilePath)
+	assert.Contains(t, content, `"level":"fatal"`)
+	assert.Contains(t, content, `"key":"value"`)
+
+	// The span should have been ended by FatalContext.
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "fatal message", spans[0].Status().Description)
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nekomeowww/xo"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// RedactedValue is the placeholder rendered in place of values logged with Redacted.
+const RedactedValue = "[REDACTED]"
+
+// Duration constructs a field with the given key and duration. The duration is
+// rendered as milliseconds in JSON (and therefore in Loki) and as a human readable
+// string such as 1.5s in the pretty format and in OpenTelemetry attributes.
+func Duration(key string, d time.Duration) zap.Field {
+	return zap.Duration(key, d)
+}
+
+// ByteSize constructs a field with the given key and size in bytes. The size is
+// rendered as the raw number of bytes in JSON and OpenTelemetry attributes, and as
+// a human readable IEC size such as 1.5 MiB in the pretty format. Constructing the
+// field doesn't allocate.
+func ByteSize(key string, size int64) zap.Field {
+	// the field is an ordinary int64 field to zap, the unit only marks it to be
+	// rendered as a human readable size in the pretty format.
+	return zap.Field{Key: key, Type: zapcore.Int64Type, Integer: size, Interface: byteSizeUnit{}}
+}
+
+// Proto constructs a field with the given key and protobuf message. The message is
+// encoded with protojson, rendered as a nested JSON object in JSON and as compact
+// JSON string in the pretty format and OpenTelemetry attributes. Unlike the other
+// helpers, the field allocates, both when constructed and when encoded.
+func Proto(key string, msg proto.Message) zap.Field {
+	return zap.Reflect(key, protoMessage{msg: msg})
+}
+
+// Decimal constructs a field with the given key and decimal. The decimal is always
+// rendered as a string to preserve its precision.
+func Decimal(key string, d decimal.Decimal) zap.Field {
+	return zap.Stringer(key, d)
+}
+
+// UUID constructs a field with the given key and UUID in its canonical string form.
+func UUID(key string, id uuid.UUID) zap.Field {
+	return zap.Stringer(key, id)
+}
+
+// Redacted constructs a field with the given key but never renders the value,
+// RedactedValue is rendered instead. It is useful for keeping the presence of
+// credentials, tokens or personal data visible in logs without leaking them. The
+// value is never boxed, so constructing the field doesn't allocate.
+func Redacted[T any](key string, _ T) zap.Field {
+	return zap.String(key, RedactedValue)
+}
+
+// byteSizeUnit marks the int64 fields constructed by ByteSize, it is zero sized and
+// therefore stored in the field without allocating.
+type byteSizeUnit struct{}
+
+type byteSize int64
+
+func (s byteSize) String() string {
+	units := []struct {
+		size   int64
+		suffix string
+	}{
+		{xo.UnitBytesOfTiB, "TiB"},
+		{xo.UnitBytesOfGiB, "GiB"},
+		{xo.UnitBytesOfMiB, "MiB"},
+		{xo.UnitBytesOfKiB, "KiB"},
+	}
+
+	abs := int64(s)
+	if abs < 0 {
+		abs = -abs
+	}
+
+	for _, unit := range units {
+		if abs >= unit.size {
+			return fmt.Sprintf("%.1f %s", float64(s)/float64(unit.size), unit.suffix)
+		}
+	}
+
+	return fmt.Sprintf("%d B", int64(s))
+}
+
+type protoMessage struct {
+	msg proto.Message
+}
+
+func (m protoMessage) String() string {
+	if m.msg == nil {
+		return "<nil>"
+	}
+
+	bytes, err := protojson.Marshal(m.msg)
+	if err != nil {
+		return fmt.Sprintf("<failed to marshal %T: %v>", m.msg, err)
+	}
+
+	return string(bytes)
+}
+
+func (m protoMessage) MarshalJSON() ([]byte, error) {
+	if m.msg == nil {
+		return []byte("null"), nil
+	}
+
+	return protojson.Marshal(m.msg)
+}
+
+func (m protoMessage) AttributeValue() attribute.Value {
+	return attribute.StringValue(m.String())
+}
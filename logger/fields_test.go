@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nekomeowww/xo/logger/otelzap"
+	"github.com/nekomeowww/xo/protobufs/testpb"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func encodeFieldsAsJSON(t *testing.T, fields ...zap.Field) map[string]any {
+	t.Helper()
+
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		MessageKey:     "message",
+		EncodeDuration: zapcore.MillisDurationEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+	})
+
+	buffer, err := encoder.EncodeEntry(zapcore.Entry{Message: "test"}, fields)
+	require.NoError(t, err)
+
+	decoded := make(map[string]any)
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &decoded))
+
+	return decoded
+}
+
+func TestZapField_MatchValue(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 3.14, ZapField(zap.Float64("f", 3.14)).MatchValue())
+	assert.Equal(t, float32(2.5), ZapField(zap.Float32("f", 2.5)).MatchValue())
+	assert.Equal(t, uint64(1<<63), ZapField(zap.Uint64("u", 1<<63)).MatchValue())
+	assert.Equal(t, 1500*time.Millisecond, ZapField(zap.Duration("d", 1500*time.Millisecond)).MatchValue())
+
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, loc)
+
+	matched, ok := ZapField(zap.Time("t", now)).MatchValue().(time.Time)
+	require.True(t, ok)
+	assert.True(t, now.Equal(matched))
+	assert.Equal(t, loc, matched.Location())
+}
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Duration", func(t *testing.T) {
+		t.Parallel()
+
+		field := Duration("elapsed", 1500*time.Millisecond)
+
+		assert.Equal(t, 1500.0, encodeFieldsAsJSON(t, field)["elapsed"])
+		assert.Equal(t, "1.5s", fmt.Sprint(ZapField(field).MatchValue()))
+		assert.Equal(t, []attribute.KeyValue{attribute.String("log.fields.elapsed", "1.5s")}, otelzap.AttributesFromZapField(field))
+	})
+
+	t.Run("ByteSize", func(t *testing.T) {
+		t.Parallel()
+
+		field := ByteSize("size", 3*1024*1024/2)
+
+		assert.Equal(t, float64(3*1024*1024/2), encodeFieldsAsJSON(t, field)["size"])
+		assert.Equal(t, "1.5 MiB", fmt.Sprint(ZapField(field).MatchValue()))
+		assert.Equal(t, "512 B", fmt.Sprint(ZapField(ByteSize("size", 512)).MatchValue()))
+		assert.Equal(t, []attribute.KeyValue{attribute.Int64("log.fields.size", 3*1024*1024/2)}, otelzap.AttributesFromZapField(field))
+	})
+
+	t.Run("Proto", func(t *testing.T) {
+		t.Parallel()
+
+		field := Proto("message", &testpb.PossibleOne{Property_1: "foo"})
+
+		assert.Equal(t, map[string]any{"property1": "foo"}, encodeFieldsAsJSON(t, field)["message"])
+		assert.JSONEq(t, `{"property1":"foo"}`, fmt.Sprint(ZapField(field).MatchValue()))
+
+		attrs := otelzap.AttributesFromZapField(field)
+		require.Len(t, attrs, 1)
+		assert.JSONEq(t, `{"property1":"foo"}`, attrs[0].Value.AsString())
+	})
+
+	t.Run("Decimal", func(t *testing.T) {
+		t.Parallel()
+
+		field := Decimal("amount", decimal.RequireFromString("0.10000000000000000001"))
+
+		assert.Equal(t, "0.10000000000000000001", encodeFieldsAsJSON(t, field)["amount"])
+		assert.Equal(t, "0.10000000000000000001", fmt.Sprint(ZapField(field).MatchValue()))
+		assert.Equal(t, []attribute.KeyValue{attribute.String("log.fields.amount", "0.10000000000000000001")}, otelzap.AttributesFromZapField(field))
+	})
+
+	t.Run("UUID", func(t *testing.T) {
+		t.Parallel()
+
+		id := uuid.New()
+		field := UUID("id", id)
+
+		assert.Equal(t, id.String(), encodeFieldsAsJSON(t, field)["id"])
+		assert.Equal(t, id.String(), fmt.Sprint(ZapField(field).MatchValue()))
+		assert.Equal(t, []attribute.KeyValue{attribute.String("log.fields.id", id.String())}, otelzap.AttributesFromZapField(field))
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		t.Parallel()
+
+		field := Redacted("password", "hunter2")
+
+		assert.Equal(t, RedactedValue, encodeFieldsAsJSON(t, field)["password"])
+		assert.Equal(t, RedactedValue, ZapField(field).MatchValue())
+		assert.Equal(t, []attribute.KeyValue{attribute.String("log.fields.password", RedactedValue)}, otelzap.AttributesFromZapField(field))
+	})
+
+	t.Run("Float", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []attribute.KeyValue{attribute.Float64("log.fields.ratio", 0.5)}, otelzap.AttributesFromZapField(zap.Float32("ratio", 0.5)))
+		assert.Equal(t, []attribute.KeyValue{attribute.Float64("log.fields.ratio", 0.25)}, otelzap.AttributesFromZapField(zap.Float64("ratio", 0.25)))
+	})
+}
+
+// TestFieldsAllocations is not parallel since testing.AllocsPerRun counts the
+// allocations of the whole program.
+func TestFieldsAllocations(t *testing.T) {
+	secret := uuid.NewString()
+	size := int64(len(secret)) * 1024 * 1024
+
+	var field zap.Field
+
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		field = ByteSize("size", size)
+	}))
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		field = Redacted("password", secret)
+	}))
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		field = Duration("elapsed", time.Second)
+	}))
+
+	assert.Equal(t, time.Second, time.Duration(field.Integer))
+}
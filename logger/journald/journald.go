@@ -0,0 +1,238 @@
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nekomeowww/xo/logger/syslog"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultSocketPath is the path of the native protocol socket of systemd-journald.
+const DefaultSocketPath = "/run/systemd/journal/socket"
+
+type Config struct {
+	// SocketPath of systemd-journald, defaults to DefaultSocketPath.
+	SocketPath string
+	// SyslogIdentifier is sent as SYSLOG_IDENTIFIER, defaults to the name of the executable.
+	SyslogIdentifier string
+	// FieldPrefix is prepended to the journal field names of the zap fields.
+	FieldPrefix string
+}
+
+// reservedFieldPrefix is prepended to the journal field names of the zap fields
+// colliding with reservedFieldNames.
+const reservedFieldPrefix = "FIELD_"
+
+// reservedFieldNames are the journal fields written by Core and the well-known fields
+// interpreted by systemd-journald, which the zap fields must not override. The trusted
+// fields starting with _ are never produced by FieldName.
+var reservedFieldNames = map[string]struct{}{
+	"MESSAGE":            {},
+	"MESSAGE_ID":         {},
+	"PRIORITY":           {},
+	"CODE_FILE":          {},
+	"CODE_LINE":          {},
+	"CODE_FUNC":          {},
+	"ERRNO":              {},
+	"INVOCATION_ID":      {},
+	"USER_INVOCATION_ID": {},
+	"SYSLOG_FACILITY":    {},
+	"SYSLOG_IDENTIFIER":  {},
+	"SYSLOG_PID":         {},
+	"SYSLOG_TIMESTAMP":   {},
+	"SYSLOG_RAW":         {},
+	"DOCUMENTATION":      {},
+	"TID":                {},
+	"UNIT":               {},
+	"USER_UNIT":          {},
+	"LOGGER_NAME":        {},
+	"STACK":              {},
+}
+
+// Core is a zapcore.Core that sends the log entries to systemd-journald with the
+// native journal protocol. The zap fields are sent as journal fields, their keys
+// are upper cased and characters other than A-Z, 0-9 and _ are replaced with _.
+// The fields colliding with the journal fields written by Core or interpreted by
+// systemd-journald, such as MESSAGE and PRIORITY, are prefixed with FIELD_.
+//
+// NOTICE: entries larger than the maximum datagram size of the socket are rejected,
+// passing them with a memfd is not supported.
+type Core struct {
+	zapcore.LevelEnabler
+
+	writer *writer
+	fields []zapcore.Field
+}
+
+var _ zapcore.Core = (*Core)(nil)
+
+type writer struct {
+	mutex  sync.Mutex
+	config Config
+	conn   *net.UnixConn
+	addr   *net.UnixAddr
+}
+
+// NewCore creates a new Core sending the log entries to the journal socket of cfg.
+func NewCore(cfg Config, enabler zapcore.LevelEnabler) (*Core, error) {
+	if cfg.SocketPath == "" {
+		cfg.SocketPath = DefaultSocketPath
+	}
+	if cfg.SyslogIdentifier == "" {
+		cfg.SyslogIdentifier = os.Args[0][strings.LastIndexAny(os.Args[0], `/\`)+1:]
+	}
+
+	_, err := os.Stat(cfg.SocketPath)
+	if err != nil {
+		return nil, fmt.Errorf("journal socket %s is not available: %w", cfg.SocketPath, err)
+	}
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Core{
+		LevelEnabler: enabler,
+		writer: &writer{
+			config: cfg,
+			conn:   conn,
+			addr:   &net.UnixAddr{Name: cfg.SocketPath, Net: "unixgram"},
+		},
+	}, nil
+}
+
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	return &Core{
+		LevelEnabler: c.LevelEnabler,
+		writer:       c.writer,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *Core) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+
+	return ce
+}
+
+func (c *Core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	msg := c.writer.format(entry, append(c.fields[:len(c.fields):len(c.fields)], fields...))
+
+	c.writer.mutex.Lock()
+	defer c.writer.mutex.Unlock()
+
+	_, err := c.writer.conn.WriteToUnix(msg, c.writer.addr)
+	if err != nil {
+		return fmt.Errorf("failed to write to journal socket %s: %w", c.writer.config.SocketPath, err)
+	}
+
+	return nil
+}
+
+func (c *Core) Sync() error {
+	return nil
+}
+
+// Close closes the socket used to send entries to the journal.
+func (c *Core) Close() error {
+	return c.writer.conn.Close()
+}
+
+func (w *writer) format(entry zapcore.Entry, fields []zapcore.Field) []byte {
+	var b bytes.Buffer
+
+	writeField(&b, "MESSAGE", entry.Message)
+	writeField(&b, "PRIORITY", strconv.Itoa(int(syslog.SeverityFromZapLevel(entry.Level))))
+	writeField(&b, "SYSLOG_IDENTIFIER", w.config.SyslogIdentifier)
+
+	if entry.LoggerName != "" {
+		writeField(&b, "LOGGER_NAME", entry.LoggerName)
+	}
+	if entry.Caller.Defined {
+		writeField(&b, "CODE_FILE", entry.Caller.File)
+		writeField(&b, "CODE_LINE", strconv.Itoa(entry.Caller.Line))
+		writeField(&b, "CODE_FUNC", entry.Caller.Function)
+	}
+	if entry.Stack != "" {
+		writeField(&b, "STACK", entry.Stack)
+	}
+
+	values := syslog.FieldValues(fields)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		name, err := FieldName(w.config.FieldPrefix + k)
+		if err != nil {
+			continue
+		}
+		if _, ok := reservedFieldNames[name]; ok {
+			name = truncateFieldName(reservedFieldPrefix + name)
+		}
+
+		writeField(&b, name, values[k])
+	}
+
+	return b.Bytes()
+}
+
+// writeField writes the field with the native journal protocol, values containing
+// new lines are written with the binary safe length prefixed serialization.
+func writeField(b *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		b.WriteString(name + "=" + value + "\n")
+		return
+	}
+
+	b.WriteString(name + "\n")
+	_ = binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value + "\n")
+}
+
+// FieldName converts key to a valid journal field name, which only consists of
+// upper case letters, digits and underscores, and doesn't start with an underscore
+// or a digit.
+func FieldName(key string) (string, error) {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	name = strings.TrimLeft(name, "_0123456789")
+	if name == "" {
+		return "", errors.New("journal field name of " + strconv.Quote(key) + " is empty")
+	}
+	return truncateFieldName(name), nil
+}
+
+// truncateFieldName truncates name to the max length of the journal field names.
+func truncateFieldName(name string) string {
+	if len(name) > 64 {
+		return name[:64]
+	}
+
+	return name
+}
@@ -0,0 +1,77 @@
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFieldName(t *testing.T) {
+	t.Parallel()
+
+	name, err := FieldName("request.id")
+	require.NoError(t, err)
+	assert.Equal(t, "REQUEST_ID", name)
+
+	name, err = FieldName("_1user-name")
+	require.NoError(t, err)
+	assert.Equal(t, "USER_NAME", name)
+
+	_, err = FieldName("__")
+	require.Error(t, err)
+}
+
+func TestCore(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "journal.sock")
+
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	core, err := NewCore(Config{
+		SocketPath:       socketPath,
+		SyslogIdentifier: "xo",
+	}, zapcore.InfoLevel)
+	require.NoError(t, err)
+
+	defer core.Close()
+
+	logger := zap.New(core).With(zap.String("request.id", "abc"))
+	logger.Debug("filtered by level")
+	logger.Error("something failed",
+		zap.String("detail", "line 1\nline 2"),
+		zap.String("priority", "high"),
+		zap.String("message", "overridden"),
+	)
+
+	buffer := make([]byte, 4096)
+
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(time.Second)))
+	n, err := listener.Read(buffer)
+	require.NoError(t, err)
+
+	var expected bytes.Buffer
+
+	expected.WriteString("MESSAGE=something failed\n")
+	expected.WriteString("PRIORITY=3\n")
+	expected.WriteString("SYSLOG_IDENTIFIER=xo\n")
+	expected.WriteString("DETAIL\n")
+	require.NoError(t, binary.Write(&expected, binary.LittleEndian, uint64(len("line 1\nline 2"))))
+	expected.WriteString("line 1\nline 2\n")
+	expected.WriteString("FIELD_MESSAGE=overridden\n")
+	expected.WriteString("FIELD_PRIORITY=high\n")
+	expected.WriteString("REQUEST_ID=abc\n")
+
+	assert.Equal(t, expected.String(), string(buffer[:n]))
+}
@@ -6,18 +6,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/nekomeowww/xo/logger/journald"
 	"github.com/nekomeowww/xo/logger/loki"
 	"github.com/nekomeowww/xo/logger/otelzap"
+	"github.com/nekomeowww/xo/logger/syslog"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -47,10 +51,14 @@ func (f ZapField) MatchValue() any {
 	case zapcore.DurationType: // checked
 		return time.Duration(f.Integer)
 	case zapcore.Float64Type: // checked
-		return f.Integer
+		return math.Float64frombits(uint64(f.Integer)) //nolint:gosec
 	case zapcore.Float32Type: // checked
-		return f.Integer
+		return math.Float32frombits(uint32(f.Integer)) //nolint:gosec
 	case zapcore.Int64Type: // checked
+		if _, ok := f.Interface.(byteSizeUnit); ok {
+			return byteSize(f.Integer)
+		}
+
 		return f.Integer
 	case zapcore.Int32Type: // checked
 		return f.Integer
@@ -61,11 +69,15 @@ func (f ZapField) MatchValue() any {
 	case zapcore.StringType: // checked
 		return f.String
 	case zapcore.TimeType: // checked
+		if loc, ok := f.Interface.(*time.Location); ok && loc != nil {
+			return time.Unix(0, f.Integer).In(loc)
+		}
+
 		return time.Unix(0, f.Integer)
 	case zapcore.TimeFullType: // checked
 		return f.Interface
 	case zapcore.Uint64Type: // checked
-		return f.Integer
+		return uint64(f.Integer) //nolint:gosec
 	case zapcore.Uint32Type: // checked
 		return f.Integer
 	case zapcore.Uint16Type: // checked
@@ -73,7 +85,7 @@ func (f ZapField) MatchValue() any {
 	case zapcore.Uint8Type: // checked
 		return f.Integer
 	case zapcore.UintptrType: // checked
-		return f.Integer
+		return uintptr(f.Integer) //nolint:gosec
 	case zapcore.ReflectType: // checked
 		return f.Interface
 	case zapcore.NamespaceType: // checked
@@ -95,6 +107,13 @@ type Logger struct {
 	LogrusLogger *logrus.Entry
 	ZapLogger    *zap.Logger
 	otelTracer   trace.Tracer
+	audit        *AuditLogger
+
+	tracerProvider trace.TracerProvider
+
+	meterProvider      metric.MeterProvider
+	attributeConverter *otelzap.Converter
+	closers            []io.Closer
 
 	withAppendedFields    []zap.Field
 	openTelemetryDisabled bool
@@ -246,7 +265,9 @@ func (l *Logger) ErrorContext(ctx context.Context, msg string, fields ...zapcore
 // Fatal logs a message at FatalLevel. The message includes any fields passed
 // at the log site, as well as any fields accumulated on the logger.
 //
-// NOTICE: This method calls os.Exit(1) to exit the program. It also prioritizes the execution of logrus' Fatal method over zap's Fatal method.
+// NOTICE: This method calls os.Exit(1) to exit the program. Before exiting, the message
+// is written to both logrus and zap, and all the sinks (including loki and the global
+// OpenTelemetry tracer provider) are flushed synchronously, see Flush.
 func (l *Logger) Fatal(msg string, fields ...zapcore.Field) {
 	data := make(map[string]any)
 	for k, v := range l.LogrusLogger.Data {
@@ -264,21 +285,73 @@ func (l *Logger) Fatal(msg string, fields ...zapcore.Field) {
 		entry = entry.WithField(v.Key, ZapField(v).MatchValue())
 	}
 
-	entry.Fatal(msg)
-	l.ZapLogger.Fatal(msg, fields...)
+	// Entry.Log doesn't call os.Exit(1) like Entry.Fatal does, the exit
+	// is deferred to the fatal hook of zap once every sink is flushed.
+	entry.Log(logrus.FatalLevel, msg)
+	l.ZapLogger.WithOptions(zap.WithFatalHook(flushThenExitHook{logger: l})).Fatal(msg, fields...)
 }
 
 // FatalContext logs a message at FatalLevel. The message includes any fields passed
 // at the log site, as well as any fields accumulated on the logger. Besides that, it
-// also logs the message to the OpenTelemetry span.
+// also logs the message to the OpenTelemetry span, records the exception on it and
+// ends the span before exiting.
 func (l *Logger) FatalContext(ctx context.Context, msg string, fields ...zapcore.Field) {
 	if !l.openTelemetryDisabled {
 		l.span(ctx, zapcore.FatalLevel, msg, fields...)
+		l.recordException(ctx, errors.New(msg), true)
 	}
 
 	l.Fatal(msg, fields...)
 }
 
+// Panic logs a message at PanicLevel. The message includes any fields passed
+// at the log site, as well as any fields accumulated on the logger.
+//
+// NOTICE: This method panics with the message once it is written to both logrus and
+// zap and all the sinks are flushed synchronously, see Flush.
+func (l *Logger) Panic(msg string, fields ...zapcore.Field) {
+	data := make(map[string]any)
+	for k, v := range l.LogrusLogger.Data {
+		data[k] = v
+	}
+
+	entry := logrus.NewEntry(l.LogrusLogger.Logger)
+	SetCallFrame(entry, l.namespace, l.skip)
+
+	for k, v := range data {
+		entry = entry.WithField(k, v)
+	}
+
+	for _, v := range fields {
+		entry = entry.WithField(v.Key, ZapField(v).MatchValue())
+	}
+
+	// Entry.Log panics for PanicLevel, the panic is deferred to the
+	// panic hook of zap once every sink is flushed.
+	func() {
+		defer func() {
+			_ = recover()
+		}()
+
+		entry.Log(logrus.PanicLevel, msg)
+	}()
+
+	l.ZapLogger.WithOptions(zap.WithPanicHook(flushThenPanicHook{logger: l})).Panic(msg, fields...)
+}
+
+// PanicContext logs a message at PanicLevel. The message includes any fields passed
+// at the log site, as well as any fields accumulated on the logger. Besides that, it
+// also logs the message to the OpenTelemetry span and records the exception on it
+// before panicking.
+func (l *Logger) PanicContext(ctx context.Context, msg string, fields ...zapcore.Field) {
+	if !l.openTelemetryDisabled {
+		l.span(ctx, zapcore.PanicLevel, msg, fields...)
+		l.recordException(ctx, errors.New(msg), false)
+	}
+
+	l.Panic(msg, fields...)
+}
+
 // With creates a new logger instance that inherits the context information from the current logger.
 // Fields added to the new logger instance do not affect the current logger instance.
 func (l *Logger) With(fields ...zapcore.Field) *Logger {
@@ -300,14 +373,12 @@ func (l *Logger) With(fields ...zapcore.Field) *Logger {
 		entry = entry.WithField(v.Key, ZapField(v).MatchValue())
 	}
 
-	return &Logger{
-		ZapLogger:             newZapLogger,
-		withAppendedFields:    append(l.withAppendedFields, fields...),
-		LogrusLogger:          entry,
-		namespace:             l.namespace,
-		skip:                  l.skip,
-		openTelemetryDisabled: l.openTelemetryDisabled,
-	}
+	newLogger := *l
+	newLogger.ZapLogger = newZapLogger
+	newLogger.LogrusLogger = entry
+	newLogger.withAppendedFields = append(l.withAppendedFields[:len(l.withAppendedFields):len(l.withAppendedFields)], fields...)
+
+	return &newLogger
 }
 
 // WithAndSkip creates a new logger instance that inherits the context information from the current logger.
@@ -332,14 +403,19 @@ func (l *Logger) WithAndSkip(skip int, fields ...zapcore.Field) *Logger {
 		entry = entry.WithField(v.Key, ZapField(v).MatchValue())
 	}
 
-	return &Logger{
-		ZapLogger:             newZapLogger,
-		LogrusLogger:          entry,
-		withAppendedFields:    append(l.withAppendedFields, fields...),
-		namespace:             l.namespace,
-		skip:                  skip,
-		openTelemetryDisabled: l.openTelemetryDisabled,
-	}
+	newLogger := *l
+	newLogger.ZapLogger = newZapLogger
+	newLogger.LogrusLogger = entry
+	newLogger.withAppendedFields = append(l.withAppendedFields[:len(l.withAppendedFields):len(l.withAppendedFields)], fields...)
+	newLogger.skip = skip
+
+	return &newLogger
+}
+
+// Audit returns the audit logger configured by WithAuditLogFilePath. The returned
+// audit logger is nil when not configured, writing to it returns ErrAuditLoggerNotConfigured.
+func (l *Logger) Audit() *AuditLogger {
+	return l.audit
 }
 
 func (l *Logger) span(ctx context.Context, lvl zapcore.Level, msg string, fields ...zap.Field) {
@@ -355,13 +431,7 @@ func (l *Logger) span(ctx context.Context, lvl zapcore.Level, msg string, fields
 	attrs = append(attrs, attribute.String("log.severity", otelzap.LogSeverityFromZapLevel(lvl).String()))
 	attrs = append(attrs, attribute.String("log.message", msg))
 
-	for _, field := range l.withAppendedFields {
-		attrs = append(attrs, otelzap.AttributesFromZapField(field)...)
-	}
-
-	for _, field := range fields {
-		attrs = append(attrs, otelzap.AttributesFromZapField(field)...)
-	}
+	attrs = append(attrs, l.attributeConverter.AttributesFromZapFields(append(l.withAppendedFields[:len(l.withAppendedFields):len(l.withAppendedFields)], fields...)...)...)
 
 	if l.caller {
 		if fn, file, line, ok := runtime.Caller(l.skip + 1); ok {
@@ -488,6 +558,12 @@ type newLoggerOptions struct {
 	format                Format
 	lokiRemoteConfig      *loki.Config
 	openTelemetryDisabled bool
+	auditLogFilePath      string
+	metrics               *metricsOptions
+	syslogConfig          *syslog.Config
+	journaldConfig        *journald.Config
+	attributeConverter    *otelzap.Converter
+	tracerProvider        trace.TracerProvider
 }
 
 type NewLoggerCallOption func(*newLoggerOptions)
@@ -553,12 +629,54 @@ func WithLokiRemoteConfig(config *loki.Config) NewLoggerCallOption {
 	}
 }
 
+// WithSyslogConfig additionally writes the logs to a syslog server with RFC 5424
+// formatted messages, the zap fields are carried by the structured data. The
+// connection to the server is closed by Logger.Close.
+func WithSyslogConfig(config *syslog.Config) NewLoggerCallOption {
+	return func(o *newLoggerOptions) {
+		o.syslogConfig = config
+	}
+}
+
+// WithJournaldConfig additionally writes the logs to systemd-journald with the native
+// journal protocol, the zap fields are sent as journal fields. The socket used to send
+// the logs is closed by Logger.Close.
+func WithJournaldConfig(config *journald.Config) NewLoggerCallOption {
+	return func(o *newLoggerOptions) {
+		o.journaldConfig = config
+	}
+}
+
+// WithOpenTelemetryAttributeConverter assigns the converter used to convert the zap fields
+// into the attributes of the span events, defaults to otelzap.NewConverter().
+func WithOpenTelemetryAttributeConverter(converter *otelzap.Converter) NewLoggerCallOption {
+	return func(o *newLoggerOptions) {
+		o.attributeConverter = converter
+	}
+}
+
+// WithTracerProvider assigns the tracer provider used by Logger.StartSpan to create
+// spans, defaults to the global tracer provider.
+func WithTracerProvider(tracerProvider trace.TracerProvider) NewLoggerCallOption {
+	return func(o *newLoggerOptions) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
 func WithOpenTelemetryDisabled() NewLoggerCallOption {
 	return func(o *newLoggerOptions) {
 		o.openTelemetryDisabled = true
 	}
 }
 
+// WithAuditLogFilePath enables the audit logger returned by Logger.Audit, which writes
+// hash chained JSON lines to auditLogFilePath.
+func WithAuditLogFilePath(auditLogFilePath string) NewLoggerCallOption {
+	return func(o *newLoggerOptions) {
+		o.auditLogFilePath = auditLogFilePath
+	}
+}
+
 // NewLogger 按需创建 logger 实例。
 func NewLogger(callOpts ...NewLoggerCallOption) (*Logger, error) {
 	opts := new(newLoggerOptions)
@@ -579,22 +697,7 @@ func NewLogger(callOpts ...NewLoggerCallOption) (*Logger, error) {
 
 	config := zap.NewProductionConfig()
 	config.Level = zap.NewAtomicLevelAt(opts.level)
-	config.EncoderConfig = zapcore.EncoderConfig{
-		TimeKey:        "@timestamp",
-		LevelKey:       "level",
-		MessageKey:     "message",
-		CallerKey:      "caller",
-		FunctionKey:    "function",
-		StacktraceKey:  "stack",
-		NameKey:        "logger",
-		EncodeLevel:    zapcore.LowercaseLevelEncoder,
-		EncodeCaller:   zapcore.ShortCallerEncoder,
-		SkipLineEnding: false,
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeDuration: zapcore.MillisDurationEncoder,
-		EncodeName:     zapcore.FullNameEncoder,
-		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
-	}
+	config.EncoderConfig = newEncoderConfig()
 
 	config.InitialFields = make(map[string]any)
 	if opts.appName != "" {
@@ -637,7 +740,19 @@ func NewLogger(callOpts ...NewLoggerCallOption) (*Logger, error) {
 		config = loki.ApplyConfig(config)
 	}
 
-	zapLogger, err := config.Build(zap.WithCaller(true))
+	teeCores, err := newTeeCores(config.Level, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	zapOptions := []zap.Option{zap.WithCaller(true)}
+	if len(teeCores) > 0 {
+		zapOptions = append(zapOptions, zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(append([]zapcore.Core{c}, teeCores...)...)
+		}))
+	}
+
+	zapLogger, err := config.Build(zapOptions...)
 	if err != nil {
 		return nil, err
 	}
@@ -668,9 +783,36 @@ func NewLogger(callOpts ...NewLoggerCallOption) (*Logger, error) {
 		caller:                true,
 		stackTrace:            false,
 		openTelemetryDisabled: opts.openTelemetryDisabled,
+		attributeConverter:    opts.attributeConverter,
+	}
+	if l.attributeConverter == nil {
+		l.attributeConverter = otelzap.NewConverter()
+	}
+	for _, core := range teeCores {
+		closer, ok := core.(io.Closer)
+		if ok {
+			l.closers = append(l.closers, closer)
+		}
 	}
 	if !opts.openTelemetryDisabled {
-		l.otelTracer = otel.Tracer("github.com/nekomeowww/xo/logger")
+		l.tracerProvider = opts.tracerProvider
+		if l.tracerProvider == nil {
+			l.tracerProvider = otel.GetTracerProvider()
+		}
+
+		l.otelTracer = l.tracerProvider.Tracer("github.com/nekomeowww/xo/logger")
+	}
+	if opts.metrics != nil {
+		l.meterProvider = opts.metrics.meterProvider
+		if l.meterProvider == nil {
+			l.meterProvider = otel.GetMeterProvider()
+		}
+	}
+	if opts.auditLogFilePath != "" {
+		l.audit, err = newAuditLogger(opts.auditLogFilePath, opts)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	l.Debug("logger init successfully for both logrus and zap",
@@ -681,6 +823,68 @@ func NewLogger(callOpts ...NewLoggerCallOption) (*Logger, error) {
 	return l, nil
 }
 
+// newTeeCores creates the cores that receive the log entries in addition to the
+// outputs of the zap config.
+func newTeeCores(enabler zapcore.LevelEnabler, opts *newLoggerOptions) ([]zapcore.Core, error) {
+	cores := make([]zapcore.Core, 0)
+
+	if opts.metrics != nil {
+		core, err := newMetricsCore(enabler, opts.metrics)
+		if err != nil {
+			return nil, err
+		}
+
+		cores = append(cores, core)
+	}
+	if opts.syslogConfig != nil {
+		config := *opts.syslogConfig
+		if config.AppName == "" {
+			config.AppName = opts.appName
+		}
+
+		core, err := syslog.NewCore(config, enabler)
+		if err != nil {
+			return nil, err
+		}
+
+		cores = append(cores, core)
+	}
+	if opts.journaldConfig != nil {
+		config := *opts.journaldConfig
+		if config.SyslogIdentifier == "" {
+			config.SyslogIdentifier = opts.appName
+		}
+
+		core, err := journald.NewCore(config, enabler)
+		if err != nil {
+			return nil, err
+		}
+
+		cores = append(cores, core)
+	}
+
+	return cores, nil
+}
+
+func newEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "@timestamp",
+		LevelKey:       "level",
+		MessageKey:     "message",
+		CallerKey:      "caller",
+		FunctionKey:    "function",
+		StacktraceKey:  "stack",
+		NameKey:        "logger",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+		SkipLineEnding: false,
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeDuration: zapcore.MillisDurationEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+	}
+}
+
 func autoCreateLogFile(logFilePathStr string) error {
 	if logFilePathStr == "" {
 		return nil
@@ -2,11 +2,18 @@ package logger
 
 import (
 	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/nekomeowww/xo"
+	"github.com/nekomeowww/xo/logger/otelzap"
+	"github.com/nekomeowww/xo/logger/syslog"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel"
@@ -92,6 +99,27 @@ func TestWith(t *testing.T) {
 		logger.Error("error message")
 		newLogger.Error("error message with with")
 	})
+	t.Run("AttributeConverter", func(t *testing.T) {
+		t.Parallel()
+
+		tracerProvider, spanRecorder := newTestTracerProvider()
+
+		logger, err := NewLogger(
+			WithLogFilePath(filepath.Join(t.TempDir(), "test.log")),
+			WithOpenTelemetryAttributeConverter(otelzap.NewConverter(otelzap.WithoutPrefix())),
+		)
+		require.NoError(t, err)
+
+		ctx, span := tracerProvider.Tracer("test").Start(context.Background(), "test")
+
+		logger.With(zap.String("some_test_field", "some_test_value")).InfoContext(ctx, "info message with with")
+		span.End()
+
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 1)
+		require.Len(t, spans[0].Events(), 1)
+		assert.Contains(t, spans[0].Events()[0].Attributes, attribute.String("some_test_field", "some_test_value"))
+	})
 }
 
 func TestFormat(t *testing.T) {
@@ -200,3 +228,42 @@ func TestOpenTelemetryAndContextual(t *testing.T) {
 		}
 	})
 }
+
+func TestWithSyslogConfig(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "syslog.sock")
+
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	logger, err := NewLogger(
+		WithAppName("xo"),
+		WithFormat(FormatJSON),
+		WithSyslogConfig(&syslog.Config{Network: "unixgram", Address: socketPath}),
+	)
+	require.NoError(t, err)
+
+	logger.Info("info message", zap.String("some_test_field", "some_test_value"))
+
+	buffer := make([]byte, 4096)
+
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(time.Second)))
+	n, err := listener.Read(buffer)
+	require.NoError(t, err)
+
+	msg := string(buffer[:n])
+	assert.Contains(t, msg, " xo ")
+	assert.Contains(t, msg, `some_test_field="some_test_value"`)
+	assert.True(t, strings.HasSuffix(msg, " info message"), msg)
+
+	require.NoError(t, logger.Close(context.Background()))
+
+	logger.Info("after close")
+
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, err = listener.Read(buffer)
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+}
@@ -21,6 +21,7 @@ import (
 type ZapLoki interface {
 	Hook(e zapcore.Entry) error
 	Sink(u *url.URL) (zap.Sink, error)
+	Flush() error
 	Stop()
 	WithCreateLogger(zap.Config) (*zap.Logger, error)
 	ApplyConfig(zap.Config) zap.Config
@@ -47,6 +48,7 @@ type lokiPusher struct {
 	client    *http.Client
 	quit      chan struct{}
 	entry     chan logEntry
+	flush     chan chan error
 	waitGroup sync.WaitGroup
 	logsBatch []streamValue
 }
@@ -83,6 +85,7 @@ func New(ctx context.Context, cfg Config) ZapLoki {
 		client:    &http.Client{},
 		quit:      make(chan struct{}),
 		entry:     make(chan logEntry),
+		flush:     make(chan chan error),
 		logsBatch: make([]streamValue, 0, cfg.BatchMaxSize),
 	}
 
@@ -109,6 +112,22 @@ func (lp *lokiPusher) Sink(_ *url.URL) (zap.Sink, error) {
 	return newSink(lp), nil
 }
 
+// Flush synchronously sends the batched log lines to loki. It is a no-op once the
+// pusher has been stopped.
+func (lp *lokiPusher) Flush() error {
+	done := make(chan error, 1)
+
+	select {
+	case <-lp.ctx.Done():
+		return nil
+	case <-lp.quit:
+		return nil
+	case lp.flush <- done:
+	}
+
+	return <-done
+}
+
 // Stop stops the loki pusher.
 func (lp *lokiPusher) Stop() {
 	close(lp.quit)
@@ -179,6 +198,14 @@ func (lp *lokiPusher) run() {
 
 				lp.logsBatch = lp.logsBatch[:0]
 			}
+		case done := <-lp.flush:
+			var err error
+			if len(lp.logsBatch) > 0 {
+				err = lp.send()
+				lp.logsBatch = lp.logsBatch[:0]
+			}
+
+			done <- err
 		case <-ticker.C:
 			if len(lp.logsBatch) > 0 {
 				err := lp.send()
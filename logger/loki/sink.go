@@ -24,11 +24,7 @@ func newSink(lp *lokiPusher) lokiSink {
 }
 
 func (s sink) Sync() error {
-	if len(s.lokiPusher.logsBatch) > 0 {
-		return s.lokiPusher.send()
-	}
-
-	return nil
+	return s.lokiPusher.Flush()
 }
 func (s sink) Close() error {
 	s.lokiPusher.Stop()
@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap/zapcore"
+)
+
+const meterName = "github.com/nekomeowww/xo/logger"
+
+type metricsOptions struct {
+	meterProvider   metric.MeterProvider
+	histogramFields []string
+	keyFunc         func(entry zapcore.Entry, fields []zapcore.Field) string
+}
+
+// WithMetrics enables the log-to-metrics bridge. Every log line written by the
+// logger increments the log.records counter with the level as the attribute, see
+// WithMetricsKeyField and WithMetricsKeyFunc to count by message keys as well. The
+// global OpenTelemetry meter provider is used when meterProvider is nil.
+func WithMetrics(meterProvider metric.MeterProvider) NewLoggerCallOption {
+	return func(o *newLoggerOptions) {
+		if o.metrics == nil {
+			o.metrics = new(metricsOptions)
+		}
+
+		o.metrics.meterProvider = meterProvider
+	}
+}
+
+// WithMetricsHistogramFields records the values of the fields with the given keys
+// into histograms named log.fields.<key> when the log-to-metrics bridge is enabled
+// by WithMetrics. Duration fields are recorded in seconds, integer and float fields
+// are recorded as is, fields of other types are ignored.
+func WithMetricsHistogramFields(keys ...string) NewLoggerCallOption {
+	return func(o *newLoggerOptions) {
+		if o.metrics == nil {
+			o.metrics = new(metricsOptions)
+		}
+
+		o.metrics.histogramFields = append(o.metrics.histogramFields, keys...)
+	}
+}
+
+// WithMetricsKeyField counts the log records by the value of the string field with
+// the given key as well when the log-to-metrics bridge is enabled by WithMetrics, the
+// value is recorded as the log.key attribute of the log.records counter. The field
+// must hold a stable key, such as an event name, rather than a dynamically built
+// message, to keep the cardinality of the metric bounded.
+func WithMetricsKeyField(key string) NewLoggerCallOption {
+	return WithMetricsKeyFunc(func(_ zapcore.Entry, fields []zapcore.Field) string {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if fields[i].Key == key && fields[i].Type == zapcore.StringType {
+				return fields[i].String
+			}
+		}
+
+		return ""
+	})
+}
+
+// WithMetricsKeyFunc counts the log records by the key returned by keyFunc as well
+// when the log-to-metrics bridge is enabled by WithMetrics, the key is recorded as the
+// log.key attribute of the log.records counter, and omitted if empty. keyFunc receives
+// the fields added by With followed by the fields of the log record, and must return
+// a bounded set of keys.
+func WithMetricsKeyFunc(keyFunc func(entry zapcore.Entry, fields []zapcore.Field) string) NewLoggerCallOption {
+	return func(o *newLoggerOptions) {
+		if o.metrics == nil {
+			o.metrics = new(metricsOptions)
+		}
+
+		o.metrics.keyFunc = keyFunc
+	}
+}
+
+// metricsCore is a zapcore.Core that doesn't write anything but increments the
+// counters and records the histograms for every log entry it receives.
+type metricsCore struct {
+	zapcore.LevelEnabler
+
+	counter    metric.Int64Counter
+	histograms map[string]metric.Float64Histogram
+	keyFunc    func(entry zapcore.Entry, fields []zapcore.Field) string
+	fields     []zapcore.Field
+}
+
+var _ zapcore.Core = (*metricsCore)(nil)
+
+func newMetricsCore(enabler zapcore.LevelEnabler, opts *metricsOptions) (*metricsCore, error) {
+	meterProvider := opts.meterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+
+	meter := meterProvider.Meter(meterName)
+
+	counter, err := meter.Int64Counter("log.records",
+		metric.WithDescription("Number of log records written, by level and key."),
+		metric.WithUnit("{record}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	histograms := make(map[string]metric.Float64Histogram, len(opts.histogramFields))
+
+	for _, key := range lo.Uniq(opts.histogramFields) {
+		histograms[key], err = meter.Float64Histogram("log.fields."+key,
+			metric.WithDescription("Values of the "+key+" field of log records."),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &metricsCore{
+		LevelEnabler: enabler,
+		counter:      counter,
+		histograms:   histograms,
+		keyFunc:      opts.keyFunc,
+	}, nil
+}
+
+func (c *metricsCore) With(fields []zapcore.Field) zapcore.Core {
+	if len(c.histograms) == 0 && c.keyFunc == nil {
+		return c
+	}
+
+	return &metricsCore{
+		LevelEnabler: c.LevelEnabler,
+		counter:      c.counter,
+		histograms:   c.histograms,
+		keyFunc:      c.keyFunc,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *metricsCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+
+	return ce
+}
+
+func (c *metricsCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	allFields := fields
+	if len(c.fields) > 0 {
+		allFields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	}
+
+	kvs := []attribute.KeyValue{attribute.String("log.severity", entry.Level.String())}
+	if c.keyFunc != nil {
+		key := c.keyFunc(entry, allFields)
+		if key != "" {
+			kvs = append(kvs, attribute.String("log.key", key))
+		}
+	}
+
+	attrs := metric.WithAttributeSet(attribute.NewSet(kvs...))
+
+	ctx := context.Background()
+	c.counter.Add(ctx, 1, attrs)
+
+	for _, field := range allFields {
+		histogram, ok := c.histograms[field.Key]
+		if !ok {
+			continue
+		}
+
+		value, ok := histogramValueOf(field)
+		if !ok {
+			continue
+		}
+
+		histogram.Record(ctx, value, attrs)
+	}
+
+	return nil
+}
+
+func (c *metricsCore) Sync() error {
+	return nil
+}
+
+func histogramValueOf(field zapcore.Field) (float64, bool) {
+	switch field.Type { //nolint:exhaustive
+	case zapcore.DurationType:
+		return time.Duration(field.Integer).Seconds(), true
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return float64(field.Integer), true
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		return float64(uint64(field.Integer)), true //nolint:gosec
+	case zapcore.Float64Type:
+		return math.Float64frombits(uint64(field.Integer)), true //nolint:gosec
+	case zapcore.Float32Type:
+		return float64(math.Float32frombits(uint32(field.Integer))), true //nolint:gosec
+	default:
+		return 0, false
+	}
+}
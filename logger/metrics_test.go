@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func findMetric(t *testing.T, resourceMetrics metricdata.ResourceMetrics, name string) metricdata.Metrics {
+	t.Helper()
+
+	for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+		for _, m := range scopeMetrics.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+
+	require.FailNow(t, "metric not found", name)
+
+	return metricdata.Metrics{}
+}
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	collectCounts := func(t *testing.T, reader *sdkmetric.ManualReader) (metricdata.ResourceMetrics, map[string]int64) {
+		t.Helper()
+
+		var resourceMetrics metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &resourceMetrics))
+
+		counter, ok := findMetric(t, resourceMetrics, "log.records").Data.(metricdata.Sum[int64])
+		require.True(t, ok)
+
+		counts := make(map[string]int64)
+
+		for _, dataPoint := range counter.DataPoints {
+			// the messages are never recorded to keep the cardinality bounded.
+			assert.False(t, dataPoint.Attributes.HasValue(attribute.Key("log.message")))
+
+			severity, _ := dataPoint.Attributes.Value(attribute.Key("log.severity"))
+			key, _ := dataPoint.Attributes.Value(attribute.Key("log.key"))
+			counts[severity.AsString()+"/"+key.AsString()] += dataPoint.Value
+		}
+
+		return resourceMetrics, counts
+	}
+
+	t.Run("Level", func(t *testing.T) {
+		t.Parallel()
+
+		reader := sdkmetric.NewManualReader()
+		meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		logger, err := NewLogger(
+			WithLevel(zapcore.InfoLevel),
+			WithFormat(FormatJSON),
+			WithMetrics(meterProvider),
+			WithMetricsHistogramFields("duration"),
+		)
+		require.NoError(t, err)
+
+		logger.Debug("filtered by level")
+		logger.Info("request handled", zap.Duration("duration", 1500*time.Millisecond))
+		logger.Info("request handled", zap.Duration("duration", 500*time.Millisecond))
+		logger.With(zap.String("module", "test")).Error("request failed")
+
+		resourceMetrics, counts := collectCounts(t, reader)
+		assert.Equal(t, map[string]int64{
+			"info/":  2,
+			"error/": 1,
+		}, counts)
+
+		histogram, ok := findMetric(t, resourceMetrics, "log.fields.duration").Data.(metricdata.Histogram[float64])
+		require.True(t, ok)
+		require.Len(t, histogram.DataPoints, 1)
+		assert.Equal(t, uint64(2), histogram.DataPoints[0].Count)
+		assert.InDelta(t, 2.0, histogram.DataPoints[0].Sum, 1e-9)
+	})
+
+	t.Run("KeyField", func(t *testing.T) {
+		t.Parallel()
+
+		reader := sdkmetric.NewManualReader()
+		meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		logger, err := NewLogger(
+			WithLevel(zapcore.InfoLevel),
+			WithFormat(FormatJSON),
+			WithMetrics(meterProvider),
+			WithMetricsKeyField("event"),
+		)
+		require.NoError(t, err)
+
+		logger.Info("handled request 1", zap.String("event", "request.handled"))
+		logger.Info("handled request 2", zap.String("event", "request.handled"))
+		logger.With(zap.String("event", "request.failed")).Error("request 3 failed")
+		logger.Info("no key")
+
+		_, counts := collectCounts(t, reader)
+		assert.Equal(t, map[string]int64{
+			"info/request.handled": 2,
+			"error/request.failed": 1,
+			"info/":                1,
+		}, counts)
+	})
+
+	t.Run("KeyFunc", func(t *testing.T) {
+		t.Parallel()
+
+		reader := sdkmetric.NewManualReader()
+		meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		logger, err := NewLogger(
+			WithLevel(zapcore.InfoLevel),
+			WithFormat(FormatJSON),
+			WithMetrics(meterProvider),
+			WithMetricsKeyFunc(func(entry zapcore.Entry, _ []zapcore.Field) string {
+				return entry.LoggerName
+			}),
+		)
+		require.NoError(t, err)
+
+		logger.ZapLogger.Named("http").Info("handled request 1")
+		logger.ZapLogger.Named("http").Info("handled request 2")
+
+		_, counts := collectCounts(t, reader)
+		assert.Equal(t, map[string]int64{"info/http": 2}, counts)
+	})
+}
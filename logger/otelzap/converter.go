@@ -0,0 +1,157 @@
+package otelzap
+
+import (
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Converter converts zap fields into OpenTelemetry attributes. The zero value is
+// not usable, use NewConverter to create one.
+type Converter struct {
+	prefix          string
+	maxAttributes   int
+	maxValueLength  int
+	maxFlattenDepth int
+	convertFunc     func(key string, f zap.Field) ([]attribute.KeyValue, bool)
+}
+
+type ConverterOption func(*Converter)
+
+// WithPrefix sets the prefix prepended to the keys of the attributes, defaults to
+// DefaultAttributePrefix.
+func WithPrefix(prefix string) ConverterOption {
+	return func(c *Converter) {
+		c.prefix = prefix
+	}
+}
+
+// WithoutPrefix keeps the keys of the attributes the same as the keys of the zap fields,
+// which is useful when the keys of the fields are already aligned with the semantic
+// conventions.
+func WithoutPrefix() ConverterOption {
+	return WithPrefix("")
+}
+
+// WithMaxAttributes limits the number of attributes converted from a single field
+// by AttributesFromZapField, or from all the fields by AttributesFromZapFields.
+// Exceeding attributes are dropped. Zero or negative means unlimited.
+func WithMaxAttributes(maxAttributes int) ConverterOption {
+	return func(c *Converter) {
+		c.maxAttributes = maxAttributes
+	}
+}
+
+// WithMaxValueLength truncates string values, and each of the elements of string
+// slice values, to at most maxValueLength bytes without breaking UTF-8 characters.
+// Zero or negative means unlimited.
+func WithMaxValueLength(maxValueLength int) ConverterOption {
+	return func(c *Converter) {
+		c.maxValueLength = maxValueLength
+	}
+}
+
+// WithMaxFlattenDepth limits how deep the objects of zapcore.ObjectMarshaler fields
+// are flattened into dotted keys, nested objects below the depth are encoded as JSON
+// strings instead. Zero or negative means unlimited.
+func WithMaxFlattenDepth(maxFlattenDepth int) ConverterOption {
+	return func(c *Converter) {
+		c.maxFlattenDepth = maxFlattenDepth
+	}
+}
+
+// WithConvertFunc assigns a callback to convert fields of custom types. The callback
+// is invoked with the prefixed key before the built-in conversion, and the returned
+// attributes are used as is when it returns true.
+func WithConvertFunc(convertFunc func(key string, f zap.Field) ([]attribute.KeyValue, bool)) ConverterOption {
+	return func(c *Converter) {
+		c.convertFunc = convertFunc
+	}
+}
+
+// NewConverter creates a new Converter.
+func NewConverter(opts ...ConverterOption) *Converter {
+	c := &Converter{
+		prefix: DefaultAttributePrefix,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// AttributesFromZapField converts the zap field into OpenTelemetry attributes.
+func (c *Converter) AttributesFromZapField(f zap.Field) []attribute.KeyValue {
+	if c.convertFunc != nil {
+		attrs, ok := c.convertFunc(c.attributeKey(f.Key), f)
+		if ok {
+			return c.limit(attrs)
+		}
+	}
+
+	return c.limit(c.convert(f))
+}
+
+// AttributesFromZapFields converts the zap fields into OpenTelemetry attributes, the
+// max attributes limit is applied to all the converted attributes as a whole.
+func (c *Converter) AttributesFromZapFields(fields ...zap.Field) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(fields))
+
+	for _, f := range fields {
+		if c.maxAttributes > 0 && len(attrs) >= c.maxAttributes {
+			break
+		}
+
+		attrs = append(attrs, c.AttributesFromZapField(f)...)
+	}
+
+	if c.maxAttributes > 0 && len(attrs) > c.maxAttributes {
+		attrs = attrs[:c.maxAttributes]
+	}
+
+	return attrs
+}
+
+func (c *Converter) attributeKey(k string) string {
+	return c.prefix + k
+}
+
+func (c *Converter) limit(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if c.maxAttributes > 0 && len(attrs) > c.maxAttributes {
+		attrs = attrs[:c.maxAttributes]
+	}
+	if c.maxValueLength <= 0 {
+		return attrs
+	}
+
+	for i, attr := range attrs {
+		switch attr.Value.Type() { //nolint:exhaustive
+		case attribute.STRING:
+			attrs[i].Value = attribute.StringValue(truncate(attr.Value.AsString(), c.maxValueLength))
+		case attribute.STRINGSLICE:
+			values := attr.Value.AsStringSlice()
+			for j := range values {
+				values[j] = truncate(values[j], c.maxValueLength)
+			}
+
+			attrs[i].Value = attribute.StringSliceValue(values)
+		}
+	}
+
+	return attrs
+}
+
+func truncate(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+
+	for maxLength > 0 && !utf8.RuneStart(s[maxLength]) {
+		maxLength--
+	}
+
+	return s[:maxLength]
+}
@@ -0,0 +1,134 @@
+package otelzap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type nestedObject struct {
+	depth int
+}
+
+func (o nestedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("depth", o.depth)
+
+	if o.depth < 3 {
+		return enc.AddObject("child", nestedObject{depth: o.depth + 1})
+	}
+
+	return nil
+}
+
+func attributesAsMap(attrs []attribute.KeyValue) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		m[string(attr.Key)] = attr.Value.AsInterface()
+	}
+
+	return m
+}
+
+func TestConverter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.String("log.fields.key", "value")},
+			AttributesFromZapField(zap.String("key", "value")),
+		)
+	})
+
+	t.Run("WithPrefix", func(t *testing.T) {
+		t.Parallel()
+
+		converter := NewConverter(WithPrefix("app."))
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.String("app.key", "value")},
+			converter.AttributesFromZapField(zap.String("key", "value")),
+		)
+	})
+
+	t.Run("WithoutPrefix", func(t *testing.T) {
+		t.Parallel()
+
+		converter := NewConverter(WithoutPrefix())
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.String("http.request.method", "GET")},
+			converter.AttributesFromZapField(zap.String("http.request.method", "GET")),
+		)
+	})
+
+	t.Run("WithMaxAttributes", func(t *testing.T) {
+		t.Parallel()
+
+		converter := NewConverter(WithMaxAttributes(2))
+
+		assert.Len(t, converter.AttributesFromZapField(zap.Object("object", nestedObject{})), 2)
+		assert.Len(t, converter.AttributesFromZapFields(
+			zap.String("a", "a"),
+			zap.String("b", "b"),
+			zap.String("c", "c"),
+		), 2)
+	})
+
+	t.Run("WithMaxValueLength", func(t *testing.T) {
+		t.Parallel()
+
+		converter := NewConverter(WithMaxValueLength(4))
+
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.String("log.fields.key", "abcd")},
+			converter.AttributesFromZapField(zap.String("key", "abcdefg")),
+		)
+		// 你 takes 3 bytes in UTF-8, it should not be broken.
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.String("log.fields.key", "a你")},
+			converter.AttributesFromZapField(zap.String("key", "a你好")),
+		)
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.StringSlice("log.fields.key", []string{"abcd", "ab"})},
+			converter.AttributesFromZapField(zap.Strings("key", []string{"abcdefg", "ab"})),
+		)
+	})
+
+	t.Run("WithMaxFlattenDepth", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, map[string]any{
+			"log.fields.object.depth":                   int64(0),
+			"log.fields.object.child.depth":             int64(1),
+			"log.fields.object.child.child.depth":       int64(2),
+			"log.fields.object.child.child.child.depth": int64(3),
+		}, attributesAsMap(NewConverter().AttributesFromZapField(zap.Object("object", nestedObject{}))))
+
+		assert.Equal(t, map[string]any{
+			"log.fields.object.depth":       int64(0),
+			"log.fields.object.child.depth": int64(1),
+			"log.fields.object.child.child": `{"child":{"depth":3},"depth":2}`,
+		}, attributesAsMap(NewConverter(WithMaxFlattenDepth(2)).AttributesFromZapField(zap.Object("object", nestedObject{}))))
+	})
+
+	t.Run("WithConvertFunc", func(t *testing.T) {
+		t.Parallel()
+
+		converter := NewConverter(WithConvertFunc(func(key string, f zap.Field) ([]attribute.KeyValue, bool) {
+			if f.Key != "custom" {
+				return nil, false
+			}
+
+			return []attribute.KeyValue{attribute.Bool(key, true)}, true
+		}))
+
+		attrs := converter.AttributesFromZapFields(zap.String("custom", "value"), zap.String("other", "value"))
+		require.Len(t, attrs, 2)
+		assert.Equal(t, attribute.Bool("log.fields.custom", true), attrs[0])
+		assert.Equal(t, attribute.String("log.fields.other", "value"), attrs[1])
+	})
+}
@@ -0,0 +1,394 @@
+package otelzap
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// AttributeObjectEncoder implements zapcore.ObjectEncoder. It encodes the added
+// fields into typed OpenTelemetry attributes, nested objects are flattened into
+// dotted keys, and arrays of booleans, integers, floats and strings are kept as
+// typed slices.
+type AttributeObjectEncoder struct {
+	converter *Converter
+	keyPrefix string
+	depth     int
+	attrs     *[]attribute.KeyValue
+}
+
+var _ zapcore.ObjectEncoder = (*AttributeObjectEncoder)(nil)
+
+// NewAttributeObjectEncoder creates a new AttributeObjectEncoder, keyPrefix is
+// prepended to the keys of the added fields as is.
+func NewAttributeObjectEncoder(keyPrefix string) *AttributeObjectEncoder {
+	return defaultConverter.newObjectEncoder(keyPrefix, 1)
+}
+
+func (c *Converter) newObjectEncoder(keyPrefix string, depth int) *AttributeObjectEncoder {
+	attrs := make([]attribute.KeyValue, 0)
+
+	return &AttributeObjectEncoder{
+		converter: c,
+		keyPrefix: keyPrefix,
+		depth:     depth,
+		attrs:     &attrs,
+	}
+}
+
+// Result returns the encoded attributes.
+func (e *AttributeObjectEncoder) Result() []attribute.KeyValue {
+	return *e.attrs
+}
+
+func (e *AttributeObjectEncoder) add(key string, value attribute.Value) {
+	*e.attrs = append(*e.attrs, attribute.KeyValue{Key: attribute.Key(e.keyPrefix + key), Value: value})
+}
+
+func (e *AttributeObjectEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	value, err := arrayValueOf(v)
+	e.add(key, value)
+
+	return err
+}
+
+func (e *AttributeObjectEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	if e.converter.maxFlattenDepth > 0 && e.depth >= e.converter.maxFlattenDepth {
+		value, err := jsonValueOfObject(v)
+		e.add(key, value)
+
+		return err
+	}
+
+	nested := &AttributeObjectEncoder{
+		converter: e.converter,
+		keyPrefix: e.keyPrefix + key + ".",
+		depth:     e.depth + 1,
+		attrs:     e.attrs,
+	}
+
+	return v.MarshalLogObject(nested)
+}
+
+func (e *AttributeObjectEncoder) AddBinary(key string, v []byte) {
+	e.add(key, attribute.StringValue(base64.StdEncoding.EncodeToString(v)))
+}
+
+func (e *AttributeObjectEncoder) AddByteString(key string, v []byte) {
+	e.add(key, attribute.StringValue(string(v)))
+}
+
+func (e *AttributeObjectEncoder) AddBool(key string, v bool) {
+	e.add(key, attribute.BoolValue(v))
+}
+
+func (e *AttributeObjectEncoder) AddComplex128(key string, v complex128) {
+	e.add(key, attribute.StringValue(fmt.Sprintf("%v", v)))
+}
+
+func (e *AttributeObjectEncoder) AddComplex64(key string, v complex64) {
+	e.add(key, attribute.StringValue(fmt.Sprintf("%v", v)))
+}
+
+func (e *AttributeObjectEncoder) AddDuration(key string, v time.Duration) {
+	e.add(key, attribute.StringValue(v.String()))
+}
+
+func (e *AttributeObjectEncoder) AddFloat64(key string, v float64) {
+	e.add(key, attribute.Float64Value(v))
+}
+
+func (e *AttributeObjectEncoder) AddFloat32(key string, v float32) {
+	e.add(key, attribute.Float64Value(float64(v)))
+}
+
+func (e *AttributeObjectEncoder) AddInt(key string, v int) {
+	e.add(key, attribute.IntValue(v))
+}
+
+func (e *AttributeObjectEncoder) AddInt64(key string, v int64) {
+	e.add(key, attribute.Int64Value(v))
+}
+
+func (e *AttributeObjectEncoder) AddInt32(key string, v int32) {
+	e.add(key, attribute.Int64Value(int64(v)))
+}
+
+func (e *AttributeObjectEncoder) AddInt16(key string, v int16) {
+	e.add(key, attribute.Int64Value(int64(v)))
+}
+
+func (e *AttributeObjectEncoder) AddInt8(key string, v int8) {
+	e.add(key, attribute.Int64Value(int64(v)))
+}
+
+func (e *AttributeObjectEncoder) AddString(key, v string) {
+	e.add(key, attribute.StringValue(v))
+}
+
+func (e *AttributeObjectEncoder) AddTime(key string, v time.Time) {
+	e.add(key, attribute.StringValue(v.Format(time.RFC3339Nano)))
+}
+
+func (e *AttributeObjectEncoder) AddUint(key string, v uint) {
+	e.AddUint64(key, uint64(v))
+}
+
+func (e *AttributeObjectEncoder) AddUint64(key string, v uint64) {
+	e.add(key, uint64Value(v))
+}
+
+func (e *AttributeObjectEncoder) AddUint32(key string, v uint32) {
+	e.add(key, attribute.Int64Value(int64(v)))
+}
+
+func (e *AttributeObjectEncoder) AddUint16(key string, v uint16) {
+	e.add(key, attribute.Int64Value(int64(v)))
+}
+
+func (e *AttributeObjectEncoder) AddUint8(key string, v uint8) {
+	e.add(key, attribute.Int64Value(int64(v)))
+}
+
+func (e *AttributeObjectEncoder) AddUintptr(key string, v uintptr) {
+	e.AddUint64(key, uint64(v))
+}
+
+func (e *AttributeObjectEncoder) AddReflected(key string, v interface{}) error {
+	value, err := reflectedValueOf(v)
+	e.add(key, value)
+
+	return err
+}
+
+// OpenNamespace prefixes the keys of the fields added afterwards with key.
+func (e *AttributeObjectEncoder) OpenNamespace(key string) {
+	e.keyPrefix += key + "."
+}
+
+// attributeArrayEncoder implements zapcore.ArrayEncoder. It collects the appended
+// values to be converted into a typed slice attribute value.
+type attributeArrayEncoder struct {
+	values []any
+}
+
+var _ zapcore.ArrayEncoder = (*attributeArrayEncoder)(nil)
+
+func arrayValueOf(v zapcore.ArrayMarshaler) (attribute.Value, error) {
+	encoder := &attributeArrayEncoder{values: make([]any, 0)}
+	err := v.MarshalLogArray(encoder)
+
+	return encoder.value(), err
+}
+
+// value returns a typed slice when all the values are of the same type, integers
+// mixed with floats are converted to floats, anything else falls back to strings.
+func (e *attributeArrayEncoder) value() attribute.Value {
+	var bools, ints, floats, strs int
+
+	for _, v := range e.values {
+		switch v.(type) {
+		case bool:
+			bools++
+		case int64:
+			ints++
+		case float64:
+			floats++
+		default:
+			strs++
+		}
+	}
+
+	switch {
+	case len(e.values) == 0:
+		return attribute.StringSliceValue([]string{})
+	case bools == len(e.values):
+		return attribute.BoolSliceValue(lo.Map(e.values, func(v any, _ int) bool { return v.(bool) })) //nolint:forcetypeassert
+	case ints == len(e.values):
+		return attribute.Int64SliceValue(lo.Map(e.values, func(v any, _ int) int64 { return v.(int64) })) //nolint:forcetypeassert
+	case ints+floats == len(e.values):
+		return attribute.Float64SliceValue(lo.Map(e.values, func(v any, _ int) float64 {
+			if i, ok := v.(int64); ok {
+				return float64(i)
+			}
+
+			return v.(float64) //nolint:forcetypeassert
+		}))
+	default:
+		return attribute.StringSliceValue(lo.Map(e.values, func(v any, _ int) string {
+			if s, ok := v.(string); ok {
+				return s
+			}
+
+			return fmt.Sprintf("%v", v)
+		}))
+	}
+}
+
+func (e *attributeArrayEncoder) AppendBool(v bool) {
+	e.values = append(e.values, v)
+}
+
+func (e *attributeArrayEncoder) AppendByteString(v []byte) {
+	e.values = append(e.values, string(v))
+}
+
+func (e *attributeArrayEncoder) AppendComplex128(v complex128) {
+	e.values = append(e.values, fmt.Sprintf("%v", v))
+}
+
+func (e *attributeArrayEncoder) AppendComplex64(v complex64) {
+	e.values = append(e.values, fmt.Sprintf("%v", v))
+}
+
+func (e *attributeArrayEncoder) AppendFloat64(v float64) {
+	e.values = append(e.values, v)
+}
+
+func (e *attributeArrayEncoder) AppendFloat32(v float32) {
+	e.values = append(e.values, float64(v))
+}
+
+func (e *attributeArrayEncoder) AppendInt(v int) {
+	e.values = append(e.values, int64(v))
+}
+
+func (e *attributeArrayEncoder) AppendInt64(v int64) {
+	e.values = append(e.values, v)
+}
+
+func (e *attributeArrayEncoder) AppendInt32(v int32) {
+	e.values = append(e.values, int64(v))
+}
+
+func (e *attributeArrayEncoder) AppendInt16(v int16) {
+	e.values = append(e.values, int64(v))
+}
+
+func (e *attributeArrayEncoder) AppendInt8(v int8) {
+	e.values = append(e.values, int64(v))
+}
+
+func (e *attributeArrayEncoder) AppendString(v string) {
+	e.values = append(e.values, v)
+}
+
+func (e *attributeArrayEncoder) AppendUint(v uint) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *attributeArrayEncoder) AppendUint32(v uint32) {
+	e.values = append(e.values, int64(v))
+}
+
+func (e *attributeArrayEncoder) AppendUint16(v uint16) {
+	e.values = append(e.values, int64(v))
+}
+
+func (e *attributeArrayEncoder) AppendUint8(v uint8) {
+	e.values = append(e.values, int64(v))
+}
+
+func (e *attributeArrayEncoder) AppendUintptr(v uintptr) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *attributeArrayEncoder) AppendUint64(v uint64) {
+	if v > math.MaxInt64 {
+		e.values = append(e.values, fmt.Sprintf("%d", v))
+		return
+	}
+
+	e.values = append(e.values, int64(v))
+}
+
+func (e *attributeArrayEncoder) AppendDuration(v time.Duration) {
+	e.values = append(e.values, v.String())
+}
+
+func (e *attributeArrayEncoder) AppendTime(v time.Time) {
+	e.values = append(e.values, v.Format(time.RFC3339Nano))
+}
+
+// AppendArray appends the nested array as a JSON string since attribute values
+// can't be nested.
+func (e *attributeArrayEncoder) AppendArray(v zapcore.ArrayMarshaler) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	err := encoder.AddArray("array", v)
+
+	bytes, jsonErr := json.Marshal(encoder.Fields["array"])
+	if jsonErr != nil {
+		e.values = append(e.values, fmt.Sprintf("%v", encoder.Fields["array"]))
+		return err
+	}
+
+	e.values = append(e.values, string(bytes))
+
+	return err
+}
+
+// AppendObject appends the object as a JSON string since attribute values
+// can't be nested.
+func (e *attributeArrayEncoder) AppendObject(v zapcore.ObjectMarshaler) error {
+	value, err := jsonValueOfObject(v)
+	e.values = append(e.values, value.AsString())
+
+	return err
+}
+
+func (e *attributeArrayEncoder) AppendReflected(v interface{}) error {
+	value, err := reflectedValueOf(v)
+	e.values = append(e.values, value.AsInterface())
+
+	return err
+}
+
+func uint64Value(v uint64) attribute.Value {
+	if v > math.MaxInt64 {
+		return attribute.StringValue(fmt.Sprintf("%d", v))
+	}
+
+	return attribute.Int64Value(int64(v))
+}
+
+func jsonValueOfObject(v zapcore.ObjectMarshaler) (attribute.Value, error) {
+	encoder := zapcore.NewMapObjectEncoder()
+	err := v.MarshalLogObject(encoder)
+
+	bytes, jsonErr := json.Marshal(encoder.Fields)
+	if jsonErr != nil {
+		return attribute.StringValue(fmt.Sprintf("%v", encoder.Fields)), err
+	}
+
+	return attribute.StringValue(string(bytes)), err
+}
+
+func reflectedValueOf(v any) (attribute.Value, error) {
+	if valuer, ok := v.(AttributeValuer); ok && !lo.IsNil(valuer) {
+		return valuer.AttributeValue(), nil
+	}
+
+	switch val := v.(type) {
+	case string:
+		return attribute.StringValue(val), nil
+	case fmt.Stringer:
+		if lo.IsNil(val) {
+			return attribute.StringValue("<nil>"), nil
+		}
+
+		return attribute.StringValue(val.String()), nil
+	}
+
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return attribute.StringValue(fmt.Sprintf("%v", v)), nil //nolint:nilerr
+	}
+
+	return attribute.StringValue(string(bytes)), nil
+}
@@ -0,0 +1,167 @@
+package otelzap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type user struct {
+	ID      int64
+	Name    string
+	Scores  []float64
+	Tags    []string
+	Flags   []bool
+	Address address
+}
+
+func (u user) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("id", u.ID)
+	enc.AddString("name", u.Name)
+	enc.AddDuration("timeout", time.Second)
+
+	err := enc.AddArray("scores", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		for _, score := range u.Scores {
+			enc.AppendFloat64(score)
+		}
+
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+
+	err = enc.AddArray("tags", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		for _, tag := range u.Tags {
+			enc.AppendString(tag)
+		}
+
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+
+	err = enc.AddArray("flags", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		for _, flag := range u.Flags {
+			enc.AppendBool(flag)
+		}
+
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+
+	return enc.AddObject("address", u.Address)
+}
+
+type address struct {
+	City string
+	Zip  int
+}
+
+func (a address) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("city", a.City)
+	enc.AddInt("zip", a.Zip)
+
+	return nil
+}
+
+func TestAttributeObjectEncoder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Object", func(t *testing.T) {
+		t.Parallel()
+
+		attrs := AttributesFromZapField(zap.Object("user", user{
+			ID:      1,
+			Name:    "neko",
+			Scores:  []float64{1.5, 2.5},
+			Tags:    []string{"a", "b"},
+			Flags:   []bool{true, false},
+			Address: address{City: "Tokyo", Zip: 100},
+		}))
+
+		assert.ElementsMatch(t, []attribute.KeyValue{
+			attribute.Int64("log.fields.user.id", 1),
+			attribute.String("log.fields.user.name", "neko"),
+			attribute.String("log.fields.user.timeout", "1s"),
+			attribute.Float64Slice("log.fields.user.scores", []float64{1.5, 2.5}),
+			attribute.StringSlice("log.fields.user.tags", []string{"a", "b"}),
+			attribute.BoolSlice("log.fields.user.flags", []bool{true, false}),
+			attribute.String("log.fields.user.address.city", "Tokyo"),
+			attribute.Int64("log.fields.user.address.zip", 100),
+		}, attrs)
+	})
+
+	t.Run("Inline", func(t *testing.T) {
+		t.Parallel()
+
+		attrs := AttributesFromZapField(zap.Inline(address{City: "Tokyo", Zip: 100}))
+
+		assert.ElementsMatch(t, []attribute.KeyValue{
+			attribute.String("log.fields.city", "Tokyo"),
+			attribute.Int64("log.fields.zip", 100),
+		}, attrs)
+	})
+
+	t.Run("Array", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.Int64Slice("log.fields.ints", []int64{1, 2, 3})},
+			AttributesFromZapField(zap.Ints("ints", []int{1, 2, 3})),
+		)
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.BoolSlice("log.fields.bools", []bool{true, false})},
+			AttributesFromZapField(zap.Bools("bools", []bool{true, false})),
+		)
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.Float64Slice("log.fields.floats", []float64{1, 2.5})},
+			AttributesFromZapField(zap.Float64s("floats", []float64{1, 2.5})),
+		)
+		assert.Equal(t,
+			[]attribute.KeyValue{attribute.StringSlice("log.fields.objects", []string{`{"city":"Tokyo","zip":100}`})},
+			AttributesFromZapField(zap.Objects("objects", []address{{City: "Tokyo", Zip: 100}})),
+		)
+	})
+
+	t.Run("MixedArray", func(t *testing.T) {
+		t.Parallel()
+
+		attrs := AttributesFromZapField(zap.Array("mixed", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			enc.AppendInt(1)
+			enc.AppendFloat64(1.5)
+
+			return nil
+		})))
+		assert.Equal(t, []attribute.KeyValue{attribute.Float64Slice("log.fields.mixed", []float64{1, 1.5})}, attrs)
+
+		attrs = AttributesFromZapField(zap.Array("mixed", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			enc.AppendInt(1)
+			enc.AppendString("a")
+
+			return nil
+		})))
+		assert.Equal(t, []attribute.KeyValue{attribute.StringSlice("log.fields.mixed", []string{"1", "a"})}, attrs)
+	})
+
+	t.Run("OpenNamespace", func(t *testing.T) {
+		t.Parallel()
+
+		encoder := NewAttributeObjectEncoder("")
+		encoder.AddString("a", "a")
+		encoder.OpenNamespace("ns")
+		encoder.AddString("b", "b")
+
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("a", "a"),
+			attribute.String("ns.b", "b"),
+		}, encoder.Result())
+	})
+}
@@ -38,96 +38,116 @@ func LogSeverityFromZapLevel(level zapcore.Level) log.Severity {
 	}
 }
 
-func attributeKey(k string) string {
-	return "log.fields." + k
+// AttributeValuer can be implemented by values carried by zap.Reflect fields
+// to control how they are represented as OpenTelemetry attribute values.
+type AttributeValuer interface {
+	AttributeValue() attribute.Value
 }
 
+// DefaultAttributePrefix is the prefix prepended to the keys of the attributes
+// converted from zap fields by default.
+const DefaultAttributePrefix = "log.fields."
+
+var defaultConverter = NewConverter()
+
+// AttributesFromZapField converts the zap field into OpenTelemetry attributes with
+// the default Converter, the keys are prefixed with DefaultAttributePrefix.
 func AttributesFromZapField(f zap.Field) []attribute.KeyValue {
+	return defaultConverter.AttributesFromZapField(f)
+}
+
+func (c *Converter) convert(f zap.Field) []attribute.KeyValue {
 	switch f.Type {
 	case zapcore.BoolType:
 		return []attribute.KeyValue{
-			attribute.Bool(attributeKey(f.Key), f.Integer == 1),
+			attribute.Bool(c.attributeKey(f.Key), f.Integer == 1),
 		}
 	case zapcore.Int8Type, zapcore.Int16Type, zapcore.Int32Type, zapcore.Int64Type,
 		zapcore.Uint32Type, zapcore.Uint8Type, zapcore.Uint16Type, zapcore.Uint64Type,
 		zapcore.UintptrType:
 		return []attribute.KeyValue{
-			attribute.Int64(attributeKey(f.Key), f.Integer),
+			attribute.Int64(c.attributeKey(f.Key), f.Integer),
 		}
-	case zapcore.Float32Type, zapcore.Float64Type:
+	case zapcore.Float64Type:
 		return []attribute.KeyValue{
-			attribute.Float64(attributeKey(f.Key), math.Float64frombits(uint64(f.Integer))), //nolint
+			attribute.Float64(c.attributeKey(f.Key), math.Float64frombits(uint64(f.Integer))), //nolint
+		}
+	case zapcore.Float32Type:
+		return []attribute.KeyValue{
+			attribute.Float64(c.attributeKey(f.Key), float64(math.Float32frombits(uint32(f.Integer)))), //nolint
 		}
 	case zapcore.Complex64Type, zapcore.Complex128Type:
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
+			attribute.String(c.attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
 		}
 	case zapcore.StringType:
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), f.String),
+			attribute.String(c.attributeKey(f.Key), f.String),
 		}
 	case zapcore.StringerType:
 		val, ok := f.Interface.(fmt.Stringer)
 		if !ok {
 			return []attribute.KeyValue{
-				attribute.String(attributeKey(f.Key), fmt.Sprintf("expected fmt.Stringer, got %T, v: %v", f.Interface, f.Interface)),
+				attribute.String(c.attributeKey(f.Key), fmt.Sprintf("expected fmt.Stringer, got %T, v: %v", f.Interface, f.Interface)),
 			}
 		}
 		if lo.IsNil(val) {
 			return []attribute.KeyValue{
-				attribute.String(attributeKey(f.Key), "<nil>"),
+				attribute.String(c.attributeKey(f.Key), "<nil>"),
 			}
 		}
 
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), fmt.Sprint(val)),
+			attribute.String(c.attributeKey(f.Key), fmt.Sprint(val)),
 		}
 	case zapcore.BinaryType, zapcore.ByteStringType:
 		val, ok := f.Interface.([]byte)
 		if !ok {
 			return []attribute.KeyValue{
-				attribute.String(attributeKey(f.Key), fmt.Sprintf("expected []byte, got %T, v: %v", f.Interface, f.Interface)),
+				attribute.String(c.attributeKey(f.Key), fmt.Sprintf("expected []byte, got %T, v: %v", f.Interface, f.Interface)),
 			}
 		}
 		if lo.IsNil(val) {
 			return []attribute.KeyValue{
-				attribute.String(attributeKey(f.Key), "<empty>"),
+				attribute.String(c.attributeKey(f.Key), "<empty>"),
 			}
 		}
 
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), base64.StdEncoding.EncodeToString(val)),
+			attribute.String(c.attributeKey(f.Key), base64.StdEncoding.EncodeToString(val)),
 		}
 	case zapcore.DurationType:
-		val, ok := f.Interface.(time.Duration)
-		if !ok {
-			return []attribute.KeyValue{
-				attribute.String(attributeKey(f.Key), fmt.Sprintf("expected time.Duration, got %T, v: %v", f.Interface, f.Interface)),
-			}
+		return []attribute.KeyValue{
+			attribute.String(c.attributeKey(f.Key), time.Duration(f.Integer).String()),
+		}
+	case zapcore.TimeType:
+		val := time.Unix(0, f.Integer)
+		if loc, ok := f.Interface.(*time.Location); ok && loc != nil {
+			val = val.In(loc)
 		}
 
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), val.String()),
+			attribute.String(c.attributeKey(f.Key), val.Format(time.RFC3339Nano)),
 		}
-	case zapcore.TimeType, zapcore.TimeFullType:
+	case zapcore.TimeFullType:
 		val, ok := f.Interface.(time.Time)
 		if !ok {
 			return []attribute.KeyValue{
-				attribute.String(attributeKey(f.Key), fmt.Sprintf("expected time.Time, got %T, v: %v", f.Interface, f.Interface)),
+				attribute.String(c.attributeKey(f.Key), fmt.Sprintf("expected time.Time, got %T, v: %v", f.Interface, f.Interface)),
 			}
 		}
 
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), val.Format(time.RFC3339Nano)),
+			attribute.String(c.attributeKey(f.Key), val.Format(time.RFC3339Nano)),
 		}
 	case zapcore.ErrorType:
 		err, ok := f.Interface.(error)
 		if !ok {
-			return []attribute.KeyValue{attribute.String(attributeKey(f.Key), fmt.Sprintf("expected error, got %T", f.Interface))}
+			return []attribute.KeyValue{attribute.String(c.attributeKey(f.Key), fmt.Sprintf("expected error, got %T", f.Interface))}
 		}
 		if lo.IsNil(err) {
 			return []attribute.KeyValue{
-				attribute.String(attributeKey(f.Key), "<nil>"),
+				attribute.String(c.attributeKey(f.Key), "<nil>"),
 			}
 		}
 
@@ -139,43 +159,54 @@ func AttributesFromZapField(f zap.Field) []attribute.KeyValue {
 		if marshaler, ok := f.Interface.(zapcore.ObjectMarshaler); ok {
 			if lo.IsNil(marshaler) {
 				return []attribute.KeyValue{
-					attribute.String(attributeKey(f.Key), "<nil>"),
+					attribute.String(c.attributeKey(f.Key), "<nil>"),
 				}
 			}
 
-			encoder := zapcore.NewMapObjectEncoder()
+			// Inline marshalers add their fields to the parent directly.
+			keyPrefix := c.attributeKey(f.Key) + "."
+			if f.Type == zapcore.InlineMarshalerType {
+				keyPrefix = c.prefix
+			}
+
+			encoder := c.newObjectEncoder(keyPrefix, 1)
 			if err := marshaler.MarshalLogObject(encoder); err == nil {
-				return flattenMap(attributeKey(f.Key), encoder.Fields)
+				return encoder.Result()
 			}
 		}
 
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
+			attribute.String(c.attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
 		}
 	case zapcore.ArrayMarshalerType:
 		if marshaler, ok := f.Interface.(zapcore.ArrayMarshaler); ok {
 			if lo.IsNil(marshaler) {
 				return []attribute.KeyValue{
-					attribute.String(attributeKey(f.Key), "<nil>"),
+					attribute.String(c.attributeKey(f.Key), "<nil>"),
 				}
 			}
 
-			encoder := NewBufferArrayEncoder()
-			if err := marshaler.MarshalLogArray(encoder); err == nil {
+			if value, err := arrayValueOf(marshaler); err == nil {
 				return []attribute.KeyValue{
-					attribute.StringSlice(attributeKey(f.Key), encoder.Result()),
+					{Key: attribute.Key(c.attributeKey(f.Key)), Value: value},
 				}
 			}
 		}
 
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
+			attribute.String(c.attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
 		}
 	case zapcore.ReflectType:
+		if valuer, ok := f.Interface.(AttributeValuer); ok && !lo.IsNil(valuer) {
+			return []attribute.KeyValue{
+				{Key: attribute.Key(c.attributeKey(f.Key)), Value: valuer.AttributeValue()},
+			}
+		}
+
 		str := fmt.Sprint(f.Interface)
 
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), str),
+			attribute.String(c.attributeKey(f.Key), str),
 		}
 	case zapcore.NamespaceType:
 		return []attribute.KeyValue{}
@@ -183,56 +214,11 @@ func AttributesFromZapField(f zap.Field) []attribute.KeyValue {
 		return []attribute.KeyValue{}
 	case zapcore.UnknownType:
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
+			attribute.String(c.attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
 		}
 	default:
 		return []attribute.KeyValue{
-			attribute.String(attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
-		}
-	}
-}
-
-func flattenMap(prefix string, m map[string]any) []attribute.KeyValue {
-	if m == nil {
-		return make([]attribute.KeyValue, 0)
-	}
-
-	attrs := make([]attribute.KeyValue, 0, len(m))
-
-	for k, v := range m {
-		key := prefix + "." + k
-		switch val := v.(type) {
-		case bool:
-			attrs = append(attrs, attribute.Bool(key, val))
-		case int:
-			attrs = append(attrs, attribute.Int(key, val))
-		case int64:
-			attrs = append(attrs, attribute.Int64(key, val))
-		case float64:
-			attrs = append(attrs, attribute.Float64(key, val))
-		case string:
-			attrs = append(attrs, attribute.String(key, val))
-		case []interface{}:
-			attrs = append(attrs, attribute.StringSlice(key, interfaceSliceToStringSlice(val)))
-		case map[string]interface{}:
-			attrs = append(attrs, flattenMap(key, val)...)
-		default:
-			attrs = append(attrs, attribute.String(key, fmt.Sprintf("%v", val)))
+			attribute.String(c.attributeKey(f.Key), fmt.Sprintf("%v", f.Interface)),
 		}
 	}
-
-	return attrs
-}
-
-func interfaceSliceToStringSlice(slice []any) []string {
-	if slice == nil {
-		return make([]string, 0)
-	}
-
-	result := make([]string, len(slice))
-	for i, v := range slice {
-		result[i] = fmt.Sprintf("%v", v)
-	}
-
-	return result
 }
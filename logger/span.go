@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SpanLogger is a Logger scoped to the span created by Logger.StartSpan, the messages
+// logged by Debug, Info, Warn and Error are written to both the logs and the span.
+type SpanLogger struct {
+	*Logger
+
+	ctx   context.Context
+	span  trace.Span
+	state *spanState
+}
+
+type spanState struct {
+	mutex      sync.Mutex
+	name       string
+	startedAt  time.Time
+	err        error
+	errMessage string
+	ended      bool
+}
+
+// StartSpan starts a span named name with the tracer of the logger, fields are set as
+// the attributes of the span and are carried by the returned logger. The returned
+// context holds the span, the returned logger logs to the span without passing the
+// context, and must be ended by calling End.
+//
+// When OpenTelemetry is disabled by WithOpenTelemetryDisabled, the span is a no-op
+// span and the returned logger only writes to the logs.
+func (l *Logger) StartSpan(ctx context.Context, name string, fields ...zapcore.Field) (context.Context, *SpanLogger) {
+	tracer := l.otelTracer
+
+	switch {
+	case l.openTelemetryDisabled:
+		tracer = noop.NewTracerProvider().Tracer("github.com/nekomeowww/xo/logger")
+	case tracer == nil:
+		tracer = otel.Tracer("github.com/nekomeowww/xo/logger")
+	}
+
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(l.attributeConverter.AttributesFromZapFields(fields...)...))
+
+	spanContext := span.SpanContext()
+	if spanContext.IsValid() {
+		fields = append(fields[:len(fields):len(fields)],
+			zap.String("trace_id", spanContext.TraceID().String()),
+			zap.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+
+	// The methods of SpanLogger call the *Context methods of Logger, which then call
+	// the plain methods, skip the frames in between to report the caller correctly.
+	spanScopedLogger := l.WithAndSkip(l.skip+2, fields...)
+	spanScopedLogger.ZapLogger = spanScopedLogger.ZapLogger.WithOptions(zap.AddCallerSkip(2))
+
+	return ctx, &SpanLogger{
+		Logger: spanScopedLogger,
+		ctx:    ctx,
+		span:   span,
+		state: &spanState{
+			name:      name,
+			startedAt: time.Now(),
+		},
+	}
+}
+
+// Context returns the context that holds the span.
+func (s *SpanLogger) Context() context.Context {
+	return s.ctx
+}
+
+// Span returns the underlying OpenTelemetry span.
+func (s *SpanLogger) Span() trace.Span {
+	return s.span
+}
+
+// Debug logs a message at DebugLevel to both the logs and the span.
+func (s *SpanLogger) Debug(msg string, fields ...zapcore.Field) {
+	s.Logger.DebugContext(s.ctx, msg, fields...)
+}
+
+// Info logs a message at InfoLevel to both the logs and the span.
+func (s *SpanLogger) Info(msg string, fields ...zapcore.Field) {
+	s.Logger.InfoContext(s.ctx, msg, fields...)
+}
+
+// Warn logs a message at WarnLevel to both the logs and the span.
+func (s *SpanLogger) Warn(msg string, fields ...zapcore.Field) {
+	s.markFailedIfNeeded(zapcore.WarnLevel, msg)
+	s.Logger.WarnContext(s.ctx, msg, fields...)
+}
+
+// Error logs a message at ErrorLevel to both the logs and the span, the span is
+// marked as failed.
+func (s *SpanLogger) Error(msg string, fields ...zapcore.Field) {
+	s.markFailedIfNeeded(zapcore.ErrorLevel, msg)
+	s.Logger.ErrorContext(s.ctx, msg, fields...)
+}
+
+// With creates a new span scoped logger that inherits the span and the context
+// information from the current logger.
+func (s *SpanLogger) With(fields ...zapcore.Field) *SpanLogger {
+	newSpanLogger := *s
+	newSpanLogger.Logger = s.Logger.With(fields...)
+
+	return &newSpanLogger
+}
+
+// RecordError records err as an exception of the span and marks the span as failed,
+// nil errors are ignored.
+func (s *SpanLogger) RecordError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.state.mutex.Lock()
+	s.state.err = err
+	s.state.errMessage = err.Error()
+	s.state.mutex.Unlock()
+
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End logs the duration and the status of the span, and then ends the span. Fields
+// are added to the logged message. Calling End more than once has no effect.
+func (s *SpanLogger) End(fields ...zapcore.Field) {
+	s.state.mutex.Lock()
+	if s.state.ended {
+		s.state.mutex.Unlock()
+		return
+	}
+
+	s.state.ended = true
+	err := s.state.err
+	errMessage := s.state.errMessage
+	duration := time.Since(s.state.startedAt)
+	s.state.mutex.Unlock()
+
+	fields = append(fields[:len(fields):len(fields)],
+		zap.String("span.name", s.state.name),
+		zap.Duration("span.duration", duration),
+	)
+
+	if errMessage == "" {
+		fields = append(fields, zap.String("span.status", "ok"))
+		s.Logger.InfoContext(s.ctx, s.state.name+" ended", fields...)
+	} else {
+		fields = append(fields, zap.String("span.status", "error"), zap.String("span.status_description", errMessage))
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+
+		s.Logger.ErrorContext(s.ctx, s.state.name+" ended", fields...)
+		// ErrorContext overrides the status description with the message.
+		s.span.SetStatus(codes.Error, errMessage)
+	}
+
+	s.span.End()
+}
+
+func (s *SpanLogger) markFailedIfNeeded(lvl zapcore.Level, msg string) {
+	if lvl < s.errorStatusLevel {
+		return
+	}
+
+	s.state.mutex.Lock()
+	defer s.state.mutex.Unlock()
+
+	if s.state.errMessage == "" {
+		s.state.errMessage = msg
+	}
+}
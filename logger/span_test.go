@@ -0,0 +1,190 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestStartSpan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		logFilePath := filepath.Join(t.TempDir(), "span.log")
+		tracerProvider, spanRecorder := newTestTracerProvider()
+
+		logger, err := NewLogger(
+			WithFormat(FormatJSON),
+			WithLogFilePath(logFilePath),
+			WithTracerProvider(tracerProvider),
+		)
+		require.NoError(t, err)
+
+		ctx, spanLogger := logger.StartSpan(context.Background(), "handle", zap.String("request_id", "abc"))
+		assert.Equal(t, spanLogger.Span().SpanContext(), trace.SpanContextFromContext(ctx))
+
+		spanLogger.Info("handling")
+		spanLogger.End()
+		spanLogger.End()
+
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "handle", spans[0].Name())
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("log.fields.request_id", "abc"))
+		require.Len(t, spans[0].Events(), 2)
+
+		content := readLogFile(t, logFilePath)
+		assert.Contains(t, content, `"message":"handling"`)
+		assert.Contains(t, content, `"message":"handle ended"`)
+		assert.Contains(t, content, `"span.status":"ok"`)
+		assert.Contains(t, content, `"span.duration"`)
+		assert.Contains(t, content, `"caller":"logger/span_test.go:`)
+		assert.Contains(t, content, `"request_id":"abc"`)
+		assert.Contains(t, content, `"trace_id":"`+spans[0].SpanContext().TraceID().String()+`"`)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		logFilePath := filepath.Join(t.TempDir(), "span.log")
+		tracerProvider, spanRecorder := newTestTracerProvider()
+
+		logger, err := NewLogger(
+			WithFormat(FormatJSON),
+			WithLogFilePath(logFilePath),
+			WithTracerProvider(tracerProvider),
+		)
+		require.NoError(t, err)
+
+		_, spanLogger := logger.StartSpan(context.Background(), "handle")
+		spanLogger.RecordError(errors.New("boom"))
+		spanLogger.End()
+
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, "boom", spans[0].Status().Description)
+
+		content := readLogFile(t, logFilePath)
+		assert.Contains(t, content, `"level":"error"`)
+		assert.Contains(t, content, `"span.status":"error"`)
+		assert.Contains(t, content, `"span.status_description":"boom"`)
+	})
+
+	t.Run("ErrorLog", func(t *testing.T) {
+		t.Parallel()
+
+		logFilePath := filepath.Join(t.TempDir(), "span.log")
+		tracerProvider, spanRecorder := newTestTracerProvider()
+
+		logger, err := NewLogger(
+			WithFormat(FormatJSON),
+			WithLogFilePath(logFilePath),
+			WithTracerProvider(tracerProvider),
+		)
+		require.NoError(t, err)
+
+		_, spanLogger := logger.StartSpan(context.Background(), "handle")
+		spanLogger.With(zap.String("key", "value")).Error("failed to handle")
+		spanLogger.End()
+
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+		content := readLogFile(t, logFilePath)
+		assert.Contains(t, content, `"span.status_description":"failed to handle"`)
+	})
+
+	t.Run("With", func(t *testing.T) {
+		t.Parallel()
+
+		tracerProvider, spanRecorder := newTestTracerProvider()
+
+		logger, err := NewLogger(
+			WithFormat(FormatJSON),
+			WithLogFilePath(filepath.Join(t.TempDir(), "span.log")),
+			WithTracerProvider(tracerProvider),
+		)
+		require.NoError(t, err)
+
+		_, spanLogger := logger.StartSpan(context.Background(), "handle")
+		spanLogger.With(zap.String("key", "value")).Info("handling")
+		spanLogger.End()
+
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		require.NotEmpty(t, spans[0].Events())
+
+		attrs := spans[0].Events()[0].Attributes
+		assert.Contains(t, attrs, attribute.String("log.fields.key", "value"))
+		assert.True(t, lo.ContainsBy(attrs, func(attr attribute.KeyValue) bool {
+			return attr.Key == "code.filepath"
+		}))
+	})
+
+	t.Run("DerivedLogger", func(t *testing.T) {
+		t.Parallel()
+
+		logFilePath := filepath.Join(t.TempDir(), "span.log")
+		tracerProvider, spanRecorder := newTestTracerProvider()
+
+		logger, err := NewLogger(
+			WithFormat(FormatJSON),
+			WithLogFilePath(logFilePath),
+			WithTracerProvider(tracerProvider),
+		)
+		require.NoError(t, err)
+
+		_, spanLogger := logger.With(zap.String("key", "value")).StartSpan(context.Background(), "handle")
+		spanLogger.Warn("failed to handle")
+		spanLogger.End()
+
+		_, spanLogger = logger.WithAndSkip(1).StartSpan(context.Background(), "handle")
+		spanLogger.End()
+
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Events()[0].Attributes, attribute.String("log.fields.key", "value"))
+
+		content := readLogFile(t, logFilePath)
+		assert.Contains(t, content, `"trace_id":"`+spans[0].SpanContext().TraceID().String()+`"`)
+	})
+
+	t.Run("OpenTelemetryDisabled", func(t *testing.T) {
+		t.Parallel()
+
+		logFilePath := filepath.Join(t.TempDir(), "span.log")
+
+		logger, err := NewLogger(
+			WithFormat(FormatJSON),
+			WithLogFilePath(logFilePath),
+			WithOpenTelemetryDisabled(),
+		)
+		require.NoError(t, err)
+
+		ctx, spanLogger := logger.StartSpan(context.Background(), "handle")
+		assert.False(t, trace.SpanFromContext(ctx).IsRecording())
+
+		spanLogger.Info("handling")
+		spanLogger.End()
+
+		content := readLogFile(t, logFilePath)
+		assert.Contains(t, content, `"message":"handle ended"`)
+		assert.NotContains(t, content, `"trace_id"`)
+	})
+}
@@ -0,0 +1,381 @@
+package syslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Facility is the syslog facility defined by RFC 5424. The kernel facility 0 is
+// reserved for the kernel messages and not provided, the zero value of Facility
+// in Config means FacilityUser.
+type Facility int
+
+const (
+	FacilityUser   Facility = 1
+	FacilityDaemon Facility = 3
+	FacilityAuth   Facility = 4
+	FacilityLocal0 Facility = 16
+	FacilityLocal1 Facility = 17
+	FacilityLocal2 Facility = 18
+	FacilityLocal3 Facility = 19
+	FacilityLocal4 Facility = 20
+	FacilityLocal5 Facility = 21
+	FacilityLocal6 Facility = 22
+	FacilityLocal7 Facility = 23
+)
+
+// Severity is the syslog severity defined by RFC 5424, it is also used as the
+// PRIORITY field of systemd-journald.
+type Severity int
+
+const (
+	SeverityEmergency     Severity = 0
+	SeverityAlert         Severity = 1
+	SeverityCritical      Severity = 2
+	SeverityError         Severity = 3
+	SeverityWarning       Severity = 4
+	SeverityNotice        Severity = 5
+	SeverityInformational Severity = 6
+	SeverityDebug         Severity = 7
+)
+
+// DefaultStructuredDataID is the SD-ID of the structured data element carrying the
+// zap fields. 32473 is the private enterprise number reserved for documentation.
+const DefaultStructuredDataID = "fields@32473"
+
+// timestampLayout is the layout of the TIMESTAMP header, RFC 5424 allows at most 6
+// digits of the fractional seconds.
+const timestampLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// SeverityFromZapLevel maps the zapcore level to the syslog severity.
+func SeverityFromZapLevel(level zapcore.Level) Severity {
+	switch level {
+	case zapcore.DebugLevel:
+		return SeverityDebug
+	case zapcore.InfoLevel:
+		return SeverityInformational
+	case zapcore.WarnLevel:
+		return SeverityWarning
+	case zapcore.ErrorLevel:
+		return SeverityError
+	case zapcore.DPanicLevel:
+		return SeverityCritical
+	case zapcore.PanicLevel:
+		return SeverityAlert
+	case zapcore.FatalLevel:
+		return SeverityEmergency
+	case zapcore.InvalidLevel:
+		return SeverityNotice
+	default:
+		return SeverityNotice
+	}
+}
+
+type Config struct {
+	// Network is one of udp, tcp, unix or unixgram.
+	Network string
+	// Address of the syslog server, e.g. localhost:514 or /dev/log.
+	Address string
+	// Facility of the messages, defaults to FacilityUser.
+	Facility Facility
+	// AppName is the APP-NAME of the messages, defaults to the name of the executable.
+	AppName string
+	// Hostname is the HOSTNAME of the messages, defaults to os.Hostname().
+	Hostname string
+	// StructuredDataID is the SD-ID of the structured data element carrying
+	// the zap fields, defaults to DefaultStructuredDataID.
+	StructuredDataID string
+}
+
+// Core is a zapcore.Core that writes RFC 5424 formatted messages to a syslog
+// server. The zap fields are carried by a structured data element. Messages are
+// framed with octet counting (RFC 6587) over stream transports (tcp and unix).
+type Core struct {
+	zapcore.LevelEnabler
+
+	writer *writer
+	fields []zapcore.Field
+}
+
+var _ zapcore.Core = (*Core)(nil)
+
+type writer struct {
+	mutex  sync.Mutex
+	config Config
+	pid    string
+	conn   net.Conn
+	closed bool
+}
+
+// NewCore dials the syslog server described by cfg and creates a new Core.
+func NewCore(cfg Config, enabler zapcore.LevelEnabler) (*Core, error) {
+	switch cfg.Network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q", cfg.Network)
+	}
+
+	if cfg.Facility == 0 {
+		cfg.Facility = FacilityUser
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = appNameOf(os.Args[0])
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname, _ = os.Hostname()
+	}
+	if cfg.StructuredDataID == "" {
+		cfg.StructuredDataID = DefaultStructuredDataID
+	}
+
+	w := &writer{
+		config: cfg,
+		pid:    strconv.Itoa(os.Getpid()),
+	}
+
+	err := w.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Core{
+		LevelEnabler: enabler,
+		writer:       w,
+	}, nil
+}
+
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	return &Core{
+		LevelEnabler: c.LevelEnabler,
+		writer:       c.writer,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *Core) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+
+	return ce
+}
+
+func (c *Core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.writer.write(entry, append(c.fields[:len(c.fields):len(c.fields)], fields...))
+}
+
+func (c *Core) Sync() error {
+	return nil
+}
+
+// Close closes the connection to the syslog server, the entries written after Close
+// are dropped with net.ErrClosed instead of dialing the server again.
+func (c *Core) Close() error {
+	c.writer.mutex.Lock()
+	defer c.writer.mutex.Unlock()
+
+	c.writer.closed = true
+
+	if c.writer.conn == nil {
+		return nil
+	}
+
+	err := c.writer.conn.Close()
+	c.writer.conn = nil
+
+	return err
+}
+
+func (w *writer) dial() error {
+	conn, err := net.Dial(w.config.Network, w.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to dial syslog server %s://%s: %w", w.config.Network, w.config.Address, err)
+	}
+
+	w.conn = conn
+
+	return nil
+}
+
+func (w *writer) isStream() bool {
+	return strings.HasPrefix(w.config.Network, "tcp") || w.config.Network == "unix"
+}
+
+func (w *writer) write(entry zapcore.Entry, fields []zapcore.Field) error {
+	msg := w.format(entry, fields)
+	if w.isStream() {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return net.ErrClosed
+	}
+
+	var errs []error
+
+	// Retry once with a new connection, the server may have been restarted.
+	for range 2 {
+		if w.conn == nil {
+			err := w.dial()
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		_, err := w.conn.Write(msg)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *writer) format(entry zapcore.Entry, fields []zapcore.Field) []byte {
+	var b bytes.Buffer
+
+	priority := int(w.config.Facility)*8 + int(SeverityFromZapLevel(entry.Level))
+
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s %s ",
+		priority,
+		entry.Time.Format(timestampLayout),
+		headerValue(w.config.Hostname, 255),
+		headerValue(w.config.AppName, 48),
+		headerValue(w.pid, 128),
+		headerValue(entry.LoggerName, 32),
+	)
+
+	values := FieldValues(fields)
+	if len(values) == 0 {
+		b.WriteByte('-')
+	} else {
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		b.WriteString("[" + w.config.StructuredDataID)
+
+		for _, k := range keys {
+			b.WriteString(" " + paramName(k) + `="`)
+			writeParamValue(&b, values[k])
+			b.WriteByte('"')
+		}
+
+		b.WriteByte(']')
+	}
+
+	if entry.Message != "" {
+		b.WriteString(" " + entry.Message)
+	}
+
+	return b.Bytes()
+}
+
+// FieldValues encodes the zap fields as a flat map of strings. Strings are kept as
+// is while other values, including nested objects and arrays, are encoded as JSON.
+func FieldValues(fields []zapcore.Field) map[string]string {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	values := make(map[string]string, len(encoder.Fields))
+
+	for k, v := range encoder.Fields {
+		switch val := v.(type) {
+		case string:
+			values[k] = val
+		case time.Duration:
+			values[k] = val.String()
+		case time.Time:
+			values[k] = val.Format(time.RFC3339Nano)
+		default:
+			bytes, err := json.Marshal(val)
+			if err != nil {
+				values[k] = fmt.Sprint(val)
+				continue
+			}
+
+			values[k] = string(bytes)
+		}
+	}
+
+	return values
+}
+
+// headerValue returns the NILVALUE for empty values, and replaces the characters
+// that are not allowed in the header fields.
+func headerValue(value string, maxLength int) string {
+	if value == "" {
+		return "-"
+	}
+
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+
+		return r
+	}, value)
+
+	if len(value) > maxLength {
+		return value[:maxLength]
+	}
+
+	return value
+}
+
+func paramName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+
+		return r
+	}, name)
+
+	if len(name) > 32 {
+		return name[:32]
+	}
+
+	return name
+}
+
+func writeParamValue(b *bytes.Buffer, value string) {
+	for _, r := range value {
+		if r == '"' || r == '\\' || r == ']' {
+			b.WriteByte('\\')
+		}
+
+		b.WriteRune(r)
+	}
+}
+
+func appNameOf(path string) string {
+	index := strings.LastIndexAny(path, `/\`)
+
+	return path[index+1:]
+}
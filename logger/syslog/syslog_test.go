@@ -0,0 +1,181 @@
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSeverityFromZapLevel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, SeverityDebug, SeverityFromZapLevel(zapcore.DebugLevel))
+	assert.Equal(t, SeverityInformational, SeverityFromZapLevel(zapcore.InfoLevel))
+	assert.Equal(t, SeverityWarning, SeverityFromZapLevel(zapcore.WarnLevel))
+	assert.Equal(t, SeverityError, SeverityFromZapLevel(zapcore.ErrorLevel))
+	assert.Equal(t, SeverityCritical, SeverityFromZapLevel(zapcore.DPanicLevel))
+	assert.Equal(t, SeverityAlert, SeverityFromZapLevel(zapcore.PanicLevel))
+	assert.Equal(t, SeverityEmergency, SeverityFromZapLevel(zapcore.FatalLevel))
+}
+
+func TestCore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Unixgram", func(t *testing.T) {
+		t.Parallel()
+
+		socketPath := filepath.Join(t.TempDir(), "syslog.sock")
+
+		listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+		require.NoError(t, err)
+
+		defer listener.Close()
+
+		core, err := NewCore(Config{
+			Network:  "unixgram",
+			Address:  socketPath,
+			Facility: FacilityLocal0,
+			AppName:  "xo",
+			Hostname: "localhost",
+		}, zapcore.InfoLevel)
+		require.NoError(t, err)
+
+		defer core.Close()
+
+		logger := zap.New(core).With(zap.String("module", `a"b]c\d`))
+		logger.Debug("filtered by level")
+		logger.Warn("something happened", zap.Int("count", 2), zap.Strings("tags", []string{"a", "b"}))
+
+		buffer := make([]byte, 4096)
+
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(time.Second)))
+		n, err := listener.Read(buffer)
+		require.NoError(t, err)
+
+		msg := string(buffer[:n])
+
+		// FacilityLocal0 * 8 + SeverityWarning
+		assert.True(t, strings.HasPrefix(msg, "<132>1 "), msg)
+		assert.Contains(t, msg, " localhost xo ")
+		assert.Contains(t, msg, `[fields@32473 count="2" module="a\"b\]c\\d" tags="[\"a\",\"b\"\]"] something happened`)
+	})
+
+	t.Run("TCP", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		defer listener.Close()
+
+		received := make(chan string, 2)
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			defer conn.Close()
+
+			reader := bufio.NewReader(conn)
+
+			for {
+				lengthStr, err := reader.ReadString(' ')
+				if err != nil {
+					return
+				}
+
+				length, err := strconv.Atoi(strings.TrimSpace(lengthStr))
+				if err != nil {
+					return
+				}
+
+				msg := make([]byte, length)
+
+				_, err = io.ReadFull(reader, msg)
+				if err != nil {
+					return
+				}
+
+				received <- string(msg)
+			}
+		}()
+
+		core, err := NewCore(Config{
+			Network: "tcp",
+			Address: listener.Addr().String(),
+			AppName: "xo",
+		}, zapcore.DebugLevel)
+		require.NoError(t, err)
+
+		defer core.Close()
+
+		logger := zap.New(core)
+		logger.Info("first")
+		logger.Error("second")
+
+		for _, expected := range []string{"<14>1 ", "<11>1 "} {
+			select {
+			case msg := <-received:
+				assert.True(t, strings.HasPrefix(msg, expected), msg)
+				assert.Contains(t, msg, " - ")
+			case <-time.After(time.Second):
+				require.FailNow(t, "timed out waiting for syslog message")
+			}
+		}
+	})
+
+	t.Run("TimestampAndClose", func(t *testing.T) {
+		t.Parallel()
+
+		socketPath := filepath.Join(t.TempDir(), "syslog.sock")
+
+		listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+		require.NoError(t, err)
+
+		defer listener.Close()
+
+		core, err := NewCore(Config{
+			Network:  "unixgram",
+			Address:  socketPath,
+			AppName:  "xo",
+			Hostname: "localhost",
+		}, zapcore.InfoLevel)
+		require.NoError(t, err)
+
+		entry := zapcore.Entry{
+			Level:   zapcore.InfoLevel,
+			Time:    time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC),
+			Message: "hello",
+		}
+		require.NoError(t, core.Write(entry, nil))
+
+		buffer := make([]byte, 4096)
+
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(time.Second)))
+		n, err := listener.Read(buffer)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(buffer[:n]), "<14>1 2024-01-02T03:04:05.123456Z localhost xo "), string(buffer[:n]))
+
+		require.NoError(t, core.Close())
+		require.ErrorIs(t, core.Write(entry, nil), net.ErrClosed)
+	})
+
+	t.Run("UnsupportedNetwork", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCore(Config{Network: "http"}, zapcore.DebugLevel)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,306 @@
+package opqcursor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMalformedCursor is returned by Codec when the cursor is not in the format of the codec.
+	ErrMalformedCursor = errors.New("opqcursor: malformed cursor")
+	// ErrTamperedCursor is returned by Codec when the signature of the cursor mismatches, the
+	// cursor fails to be decrypted, or the key of the cursor is unknown.
+	ErrTamperedCursor = errors.New("opqcursor: tampered cursor")
+	// ErrExpiredCursor is returned by Codec when the cursor is expired.
+	ErrExpiredCursor = errors.New("opqcursor: expired cursor")
+)
+
+// CodecMode is the way Codec protects the cursors.
+type CodecMode int
+
+const (
+	// CodecModeSign signs the cursors with HMAC-SHA256, the content of the cursors are
+	// readable by the clients but cannot be forged.
+	CodecModeSign CodecMode = iota
+	// CodecModeEncrypt encrypts the cursors with AES-GCM, the content of the cursors are
+	// neither readable nor forgeable by the clients.
+	CodecModeEncrypt
+)
+
+// Key is a secret used by Codec, the ID is embedded in the cursors to find the key to
+// decode them, so that the keys can be rotated without invalidating the issued cursors.
+type Key struct {
+	// ID identifies the key, it must not be empty or contain ".".
+	ID string
+	// Secret is the secret of the key. It must be 16, 24 or 32 bytes long to select
+	// AES-128, AES-192 or AES-256 for CodecModeEncrypt, and it should be at least 32 bytes
+	// long for CodecModeSign.
+	Secret []byte
+}
+
+type codecOptions struct {
+	decodeKeys []Key
+	ttl        time.Duration
+	now        func() time.Time
+}
+
+// CodecOption configures the Codec.
+type CodecOption func(*codecOptions)
+
+// WithDecodeKeys assigns the extra keys to decode the cursors, such as the retired keys
+// during key rotation. The cursors are always encoded with the primary key of the codec.
+func WithDecodeKeys(keys ...Key) CodecOption {
+	return func(o *codecOptions) {
+		o.decodeKeys = append(o.decodeKeys, keys...)
+	}
+}
+
+// WithTTL makes the cursors encoded by the codec expire after ttl.
+func WithTTL(ttl time.Duration) CodecOption {
+	return func(o *codecOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithClock assigns the function returning the current time, defaults to time.Now.
+func WithClock(now func() time.Time) CodecOption {
+	return func(o *codecOptions) {
+		o.now = now
+	}
+}
+
+// Codec encodes and decodes the cursors with signing or encryption, so that the clients
+// cannot forge the cursors, for example, changing the tenant filter of a cursor.
+type Codec struct {
+	mode    CodecMode
+	primary string
+	signers map[string][]byte
+	aeads   map[string]cipher.AEAD
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+type codecPayload struct {
+	ExpiresAt int64           `json:"exp,omitempty"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewCodec creates a codec protecting the cursors in mode, the cursors are encoded with
+// primaryKey, see WithDecodeKeys for key rotation.
+func NewCodec(mode CodecMode, primaryKey Key, opts ...CodecOption) (*Codec, error) {
+	options := &codecOptions{now: time.Now}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	c := &Codec{
+		mode:    mode,
+		primary: primaryKey.ID,
+		signers: make(map[string][]byte),
+		aeads:   make(map[string]cipher.AEAD),
+		ttl:     options.ttl,
+		now:     options.now,
+	}
+
+	for _, key := range append([]Key{primaryKey}, options.decodeKeys...) {
+		err := c.addKey(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Codec) addKey(key Key) error {
+	if key.ID == "" || strings.Contains(key.ID, ".") {
+		return fmt.Errorf("opqcursor: invalid key id %q", key.ID)
+	}
+	if len(key.Secret) == 0 {
+		return fmt.Errorf("opqcursor: empty secret of key %q", key.ID)
+	}
+
+	switch c.mode {
+	case CodecModeSign:
+		c.signers[key.ID] = key.Secret
+	case CodecModeEncrypt:
+		block, err := aes.NewCipher(key.Secret)
+		if err != nil {
+			return fmt.Errorf("opqcursor: invalid secret of key %q: %w", key.ID, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return fmt.Errorf("opqcursor: invalid secret of key %q: %w", key.ID, err)
+		}
+
+		c.aeads[key.ID] = aead
+	default:
+		return fmt.Errorf("opqcursor: unknown codec mode %d", c.mode)
+	}
+
+	return nil
+}
+
+// Encode encodes v as JSON and returns the protected cursor string, which is URL-safe.
+func (c *Codec) Encode(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	payload := codecPayload{Data: data}
+	if c.ttl > 0 {
+		payload.ExpiresAt = c.now().Add(c.ttl).Unix()
+	}
+
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	if c.mode == CodecModeEncrypt {
+		aead := c.aeads[c.primary]
+
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+
+		_, err = rand.Read(nonce)
+		if err != nil {
+			return "", err
+		}
+
+		sealed := aead.Seal(nonce, nonce, plaintext, []byte(c.primary))
+
+		return c.primary + "." + base64.RawURLEncoding.EncodeToString(sealed), nil
+	}
+
+	signed := c.primary + "." + base64.RawURLEncoding.EncodeToString(plaintext)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign(c.signers[c.primary], signed)), nil
+}
+
+// Decode verifies the cursor and decodes it into v. It returns the errors wrapping
+// ErrMalformedCursor, ErrTamperedCursor or ErrExpiredCursor if the cursor is invalid.
+func (c *Codec) Decode(cursor string, v any) error {
+	plaintext, err := c.open(cursor)
+	if err != nil {
+		return err
+	}
+
+	var payload codecPayload
+
+	err = json.Unmarshal(plaintext, &payload)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedCursor, err)
+	}
+	if payload.ExpiresAt > 0 && !c.now().Before(time.Unix(payload.ExpiresAt, 0)) {
+		return fmt.Errorf("%w: expired at %s", ErrExpiredCursor, time.Unix(payload.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	}
+
+	err = json.Unmarshal(payload.Data, v)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedCursor, err)
+	}
+
+	return nil
+}
+
+// open verifies or decrypts the cursor and returns the payload.
+func (c *Codec) open(cursor string) ([]byte, error) {
+	parts := strings.Split(cursor, ".")
+
+	if c.mode == CodecModeEncrypt {
+		if len(parts) != 2 {
+			return nil, ErrMalformedCursor
+		}
+
+		sealed, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedCursor, err)
+		}
+
+		aead, ok := c.aeads[parts[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown key id %q", ErrTamperedCursor, parts[0])
+		}
+		if len(sealed) < aead.NonceSize() {
+			return nil, ErrMalformedCursor
+		}
+
+		plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(parts[0]))
+		if err != nil {
+			return nil, ErrTamperedCursor
+		}
+
+		return plaintext, nil
+	}
+
+	if len(parts) != 3 {
+		return nil, ErrMalformedCursor
+	}
+
+	plaintext, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedCursor, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedCursor, err)
+	}
+
+	secret, ok := c.signers[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrTamperedCursor, parts[0])
+	}
+	if !hmac.Equal(signature, sign(secret, parts[0]+"."+parts[1])) {
+		return nil, ErrTamperedCursor
+	}
+
+	return plaintext, nil
+}
+
+func sign(secret []byte, signed string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+
+	return mac.Sum(nil)
+}
+
+// MarshalWithCodec encodes the filters and sorters into a cursor protected by codec.
+func MarshalWithCodec[Filters any, Sorters any](codec *Codec, filters Filters, sorters Sorters, opts ...Option) (string, error) {
+	data, err := encodeCursor(OpaqueCursor[Filters, Sorters]{Filters: filters, Sorters: sorters}, newOptions(opts))
+	if err != nil {
+		return "", err
+	}
+
+	return codec.Encode(json.RawMessage(data))
+}
+
+// UnmarshalWithCodec verifies the cursor protected by codec and returns the OpaqueCursor struct.
+func UnmarshalWithCodec[Filters any, Sorters any](codec *Codec, cursor string, opts ...Option) (*OpaqueCursor[Filters, Sorters], error) {
+	var data json.RawMessage
+
+	err := codec.Decode(cursor, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursorData OpaqueCursor[Filters, Sorters]
+
+	err = decodeCursor(data, newOptions(opts), &cursorData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cursorData, nil
+}
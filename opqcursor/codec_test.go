@@ -0,0 +1,136 @@
+package opqcursor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFilters struct {
+	TenantID string `json:"tenantId"`
+}
+
+type testSorters struct {
+	CreatedAt string `json:"createdAt"`
+}
+
+func TestCodec(t *testing.T) {
+	t.Parallel()
+
+	key1 := Key{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)}
+	key2 := Key{ID: "k2", Secret: bytes.Repeat([]byte{2}, 32)}
+
+	for _, mode := range []CodecMode{CodecModeSign, CodecModeEncrypt} {
+		t.Run(map[CodecMode]string{CodecModeSign: "Sign", CodecModeEncrypt: "Encrypt"}[mode], func(t *testing.T) {
+			t.Parallel()
+
+			codec, err := NewCodec(mode, key1)
+			require.NoError(t, err)
+
+			cursor, err := MarshalWithCodec(codec, testFilters{TenantID: "tenant"}, testSorters{CreatedAt: "DESC"})
+			require.NoError(t, err)
+			assert.NotContains(t, cursor, "+")
+			assert.NotContains(t, cursor, "/")
+			assert.NotContains(t, cursor, "=")
+
+			content, err := base64.RawURLEncoding.DecodeString(strings.Split(cursor, ".")[1])
+			require.NoError(t, err)
+
+			// the filters are readable in the signed cursors, but not in the encrypted ones.
+			assert.Equal(t, mode == CodecModeSign, bytes.Contains(content, []byte("tenant")))
+
+			decoded, err := UnmarshalWithCodec[testFilters, testSorters](codec, cursor)
+			require.NoError(t, err)
+			assert.Equal(t, "tenant", decoded.Filters.TenantID)
+			assert.Equal(t, "DESC", decoded.Sorters.CreatedAt)
+
+			t.Run("Tampered", func(t *testing.T) {
+				t.Parallel()
+
+				parts := strings.Split(cursor, ".")
+				payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+				require.NoError(t, err)
+
+				payload[len(payload)-2] ^= 1
+				parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+
+				_, err = UnmarshalWithCodec[testFilters, testSorters](codec, strings.Join(parts, "."))
+				require.ErrorIs(t, err, ErrTamperedCursor)
+			})
+
+			t.Run("Malformed", func(t *testing.T) {
+				t.Parallel()
+
+				for _, malformed := range []string{"", "k1", "k1.!!!", "k1.a.b.c", cursor + "!"} {
+					_, err := UnmarshalWithCodec[testFilters, testSorters](codec, malformed)
+					require.ErrorIs(t, err, ErrMalformedCursor, malformed)
+				}
+			})
+
+			t.Run("KeyRotation", func(t *testing.T) {
+				t.Parallel()
+
+				rotated, err := NewCodec(mode, key2, WithDecodeKeys(key1))
+				require.NoError(t, err)
+
+				// the cursors issued with the retired key are still accepted.
+				_, err = UnmarshalWithCodec[testFilters, testSorters](rotated, cursor)
+				require.NoError(t, err)
+
+				rotatedCursor, err := MarshalWithCodec(rotated, testFilters{}, testSorters{})
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(rotatedCursor, "k2."))
+
+				// the cursors issued with the new key are rejected by the old codec.
+				_, err = UnmarshalWithCodec[testFilters, testSorters](codec, rotatedCursor)
+				require.ErrorIs(t, err, ErrTamperedCursor)
+			})
+
+			t.Run("Expired", func(t *testing.T) {
+				t.Parallel()
+
+				now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+				expiring, err := NewCodec(mode, key1, WithTTL(time.Hour), WithClock(func() time.Time {
+					return now
+				}))
+				require.NoError(t, err)
+
+				expiringCursor, err := MarshalWithCodec(expiring, testFilters{}, testSorters{})
+				require.NoError(t, err)
+
+				_, err = UnmarshalWithCodec[testFilters, testSorters](expiring, expiringCursor)
+				require.NoError(t, err)
+
+				expired, err := NewCodec(mode, key1, WithClock(func() time.Time {
+					return now.Add(time.Hour)
+				}))
+				require.NoError(t, err)
+
+				_, err = UnmarshalWithCodec[testFilters, testSorters](expired, expiringCursor)
+				require.ErrorIs(t, err, ErrExpiredCursor)
+			})
+		})
+	}
+}
+
+func TestNewCodec(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCodec(CodecModeEncrypt, Key{ID: "k1", Secret: []byte("short")})
+	require.Error(t, err)
+
+	_, err = NewCodec(CodecModeSign, Key{ID: "k.1", Secret: []byte("secret")})
+	require.Error(t, err)
+
+	_, err = NewCodec(CodecModeSign, Key{ID: "k1"})
+	require.Error(t, err)
+
+	_, err = NewCodec(CodecModeSign, Key{ID: "k1", Secret: []byte("secret")}, WithDecodeKeys(Key{}))
+	require.Error(t, err)
+}
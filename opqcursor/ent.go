@@ -0,0 +1,192 @@
+package opqcursor
+
+import (
+	"context"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// EntQuery is the query builder generated by ent, such as *ent.UserQuery, where P is the
+// predicate type such as predicate.User, and O is the order option type such as user.OrderOption.
+type EntQuery[Q any, T any, P ~func(*sql.Selector), O ~func(*sql.Selector)] interface {
+	Where(ps ...P) Q
+	Order(o ...O) Q
+	Limit(limit int) Q
+	All(ctx context.Context) ([]T, error)
+}
+
+// EntPage is a page of the keyset pagination queried by PaginateEnt.
+type EntPage[T any] struct {
+	// Items are the items of the page in the order of the columns.
+	Items []T
+	// HasNext reports whether there are items after the page.
+	HasNext bool
+	// HasPrev reports whether there are items before the page.
+	HasPrev bool
+	// NextCursor is the cursor to the page after the page, it is nil if HasNext is false.
+	NextCursor *KeysetCursor
+	// PrevCursor is the cursor to the page before the page, it is nil if HasPrev is false.
+	PrevCursor *KeysetCursor
+}
+
+/*
+PaginateEnt queries a page of at most limit items after the cursor, or before the cursor
+for DirectionPrev, with the ent query builder. The ordering, the keyset predicates and
+the limit are applied to query, and one extra item is fetched to tell whether there are
+more items. The first page is queried if cursor is nil, and the last page is queried if
+cursor holds no values but DirectionPrev, such as the one returned by ConnectionArgs.Keyset
+for last without before.
+
+columns maps the sort key to the columns of the table in order, the last one is expected
+to be unique, such as the primary key, to break ties. valuesFunc returns the values of the
+columns of an item to build the cursors.
+
+	page, err := opqcursor.PaginateEnt(ctx, client.User.Query().Where(user.TenantID(tenantID)),
+		[]opqcursor.KeysetColumn{{Name: user.FieldScore, Desc: true}, {Name: user.FieldID}},
+		func(u *ent.User) []any { return []any{u.Score, u.ID} },
+		cursor,
+		20,
+	)
+
+NOTICE: the values of the cursors are compared with the columns as is after being decoded
+from JSON, where the integers are decoded as int64 and time.Time as string, prefer the
+integer representations such as Unix timestamps if the database cannot compare them.
+*/
+func PaginateEnt[Q EntQuery[Q, T, P, O], T any, P ~func(*sql.Selector), O ~func(*sql.Selector)](
+	ctx context.Context,
+	query Q,
+	columns []KeysetColumn,
+	valuesFunc func(item T) []any,
+	cursor *KeysetCursor,
+	limit int,
+) (*EntPage[T], error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("opqcursor: invalid limit %d", limit)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: no columns", ErrKeysetMismatch)
+	}
+
+	direction := DirectionNext
+	if cursor != nil && cursor.Direction == DirectionPrev {
+		direction = DirectionPrev
+	}
+
+	// the cursor without values pages from the start, or from the end for DirectionPrev.
+	positioned := cursor != nil && len(cursor.Values) > 0
+	if positioned {
+		if len(cursor.Values) != len(columns) {
+			return nil, fmt.Errorf("%w: %d values for %d columns", ErrKeysetMismatch, len(cursor.Values), len(columns))
+		}
+
+		keyset := KeysetCursor{Values: cursor.Values, Direction: direction}
+
+		query = query.Where(P(func(s *sql.Selector) {
+			s.Where(entKeysetPredicate(s, columns, keyset))
+		}))
+	}
+
+	items, err := query.
+		Order(O(func(s *sql.Selector) {
+			s.OrderBy(entKeysetOrderBy(s, columns, direction)...)
+		})).
+		Limit(limit + 1).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(items) > limit
+	if more {
+		items = items[:limit]
+	}
+
+	page := &EntPage[T]{Items: ReverseKeysetPage(items, direction)}
+	if direction == DirectionPrev {
+		page.HasPrev = more
+		page.HasNext = positioned
+	} else {
+		page.HasNext = more
+		page.HasPrev = positioned
+	}
+
+	if page.HasNext {
+		next, ok := NextKeysetCursor(page.Items, valuesFunc)
+		if ok {
+			page.NextCursor = &next
+		}
+	}
+	if page.HasPrev {
+		prev, ok := PrevKeysetCursor(page.Items, valuesFunc)
+		if ok {
+			page.PrevCursor = &prev
+		}
+	}
+
+	return page, nil
+}
+
+// entKeysetPredicate is the ent counterpart of KeysetWhere.
+func entKeysetPredicate(s *sql.Selector, columns []KeysetColumn, cursor KeysetCursor) *sql.Predicate {
+	operators := make([]string, len(columns))
+	names := make([]string, len(columns))
+	uniform := true
+
+	for i, column := range columns {
+		operators[i] = keysetOperator(column.Desc, cursor.Direction)
+		names[i] = s.C(column.Name)
+
+		if operators[i] != operators[0] {
+			uniform = false
+		}
+	}
+
+	compare := func(i int) *sql.Predicate {
+		if operators[i] == ">" {
+			return sql.GT(names[i], cursor.Values[i])
+		}
+
+		return sql.LT(names[i], cursor.Values[i])
+	}
+
+	if len(columns) == 1 {
+		return compare(0)
+	}
+	if uniform {
+		if operators[0] == ">" {
+			return sql.CompositeGT(names, cursor.Values...)
+		}
+
+		return sql.CompositeLT(names, cursor.Values...)
+	}
+
+	conditions := make([]*sql.Predicate, 0, len(columns))
+
+	for i := range columns {
+		terms := make([]*sql.Predicate, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, sql.EQ(names[j], cursor.Values[j]))
+		}
+
+		terms = append(terms, compare(i))
+		conditions = append(conditions, sql.And(terms...))
+	}
+
+	return sql.Or(conditions...)
+}
+
+// entKeysetOrderBy is the ent counterpart of KeysetOrderBy.
+func entKeysetOrderBy(s *sql.Selector, columns []KeysetColumn, direction Direction) []string {
+	orders := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		if column.Desc == (direction == DirectionPrev) {
+			orders = append(orders, sql.Asc(s.C(column.Name)))
+		} else {
+			orders = append(orders, sql.Desc(s.C(column.Name)))
+		}
+	}
+
+	return orders
+}
@@ -0,0 +1,240 @@
+package opqcursor
+
+import (
+	"context"
+	stdsql "database/sql"
+	"fmt"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+type testEntItem struct {
+	ID    int64
+	Score int64
+}
+
+type (
+	testEntItemPredicate   func(*sql.Selector)
+	testEntItemOrderOption func(*sql.Selector)
+)
+
+// testEntItemQuery mimics the query builders generated by ent.
+type testEntItemQuery struct {
+	db         *stdsql.DB
+	predicates []testEntItemPredicate
+	order      []testEntItemOrderOption
+	limit      int
+}
+
+func (q *testEntItemQuery) Where(ps ...testEntItemPredicate) *testEntItemQuery {
+	q.predicates = append(q.predicates, ps...)
+	return q
+}
+
+func (q *testEntItemQuery) Order(o ...testEntItemOrderOption) *testEntItemQuery {
+	q.order = append(q.order, o...)
+	return q
+}
+
+func (q *testEntItemQuery) Limit(limit int) *testEntItemQuery {
+	q.limit = limit
+	return q
+}
+
+func (q *testEntItemQuery) All(ctx context.Context) ([]*testEntItem, error) {
+	t := sql.Table("items")
+	selector := sql.Dialect(dialect.SQLite).Select(t.C("id"), t.C("score")).From(t)
+
+	for _, p := range q.predicates {
+		p(selector)
+	}
+	for _, o := range q.order {
+		o(selector)
+	}
+
+	if q.limit > 0 {
+		selector.Limit(q.limit)
+	}
+
+	query, args := selector.Query()
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	items := make([]*testEntItem, 0)
+
+	for rows.Next() {
+		var item testEntItem
+
+		err = rows.Scan(&item.ID, &item.Score)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+func TestPaginateEnt(t *testing.T) {
+	t.Parallel()
+
+	// the pure Go driver registered as "sqlite" keeps the test runnable without cgo.
+	db, err := stdsql.Open("sqlite", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	_, err = db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, score INTEGER NOT NULL)")
+	require.NoError(t, err)
+
+	// scores are 3, 3, 2, 2, 1, 1, 0 for ids 1 to 7.
+	for id := int64(1); id <= 7; id++ {
+		_, err = db.Exec("INSERT INTO items (id, score) VALUES (?, ?)", id, (8-id)/2)
+		require.NoError(t, err)
+	}
+
+	valuesFunc := func(item *testEntItem) []any {
+		return []any{item.Score, item.ID}
+	}
+
+	paginate := func(t *testing.T, columns []KeysetColumn, cursor *KeysetCursor, limit int) *EntPage[*testEntItem] {
+		t.Helper()
+
+		if cursor != nil {
+			// round trip the cursor as the clients do.
+			encoded, err := MarshalKeyset(*cursor)
+			require.NoError(t, err)
+
+			cursor, err = UnmarshalKeyset(encoded)
+			require.NoError(t, err)
+		}
+
+		page, err := PaginateEnt(context.Background(), &testEntItemQuery{db: db}, columns, valuesFunc, cursor, limit)
+		require.NoError(t, err)
+
+		return page
+	}
+
+	ids := func(page *EntPage[*testEntItem]) []int64 {
+		return lo.Map(page.Items, func(item *testEntItem, _ int) int64 {
+			return item.ID
+		})
+	}
+
+	testCases := []struct {
+		name    string
+		columns []KeysetColumn
+		pages   [][]int64
+	}{
+		{
+			name:    "Uniform",
+			columns: []KeysetColumn{{Name: "score"}, {Name: "id"}},
+			pages:   [][]int64{{7, 5, 6}, {3, 4, 1}, {2}},
+		},
+		{
+			name:    "UniformDesc",
+			columns: []KeysetColumn{{Name: "score", Desc: true}, {Name: "id", Desc: true}},
+			pages:   [][]int64{{2, 1, 4}, {3, 6, 5}, {7}},
+		},
+		{
+			name:    "Mixed",
+			columns: []KeysetColumn{{Name: "score", Desc: true}, {Name: "id"}},
+			pages:   [][]int64{{1, 2, 3}, {4, 5, 6}, {7}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cursor *KeysetCursor
+
+			pages := make([]*EntPage[*testEntItem], 0, len(tc.pages))
+
+			for i, expected := range tc.pages {
+				page := paginate(t, tc.columns, cursor, 3)
+				assert.Equal(t, expected, ids(page))
+				assert.Equal(t, i < len(tc.pages)-1, page.HasNext)
+				assert.Equal(t, i > 0, page.HasPrev)
+
+				pages = append(pages, page)
+				cursor = page.NextCursor
+			}
+
+			require.Nil(t, cursor)
+
+			// pages backwards from the last page.
+			cursor = pages[len(pages)-1].PrevCursor
+
+			for i := len(tc.pages) - 2; i >= 0; i-- {
+				page := paginate(t, tc.columns, cursor, 3)
+				assert.Equal(t, tc.pages[i], ids(page))
+				assert.True(t, page.HasNext)
+				assert.Equal(t, i > 0, page.HasPrev)
+
+				cursor = page.PrevCursor
+			}
+
+			require.Nil(t, cursor)
+
+			// pages from the end without a position.
+			all := lo.Flatten(tc.pages)
+
+			page := paginate(t, tc.columns, &KeysetCursor{Direction: DirectionPrev}, 3)
+			assert.Equal(t, all[len(all)-3:], ids(page))
+			assert.False(t, page.HasNext)
+			assert.True(t, page.HasPrev)
+			assert.Nil(t, page.NextCursor)
+
+			page = paginate(t, tc.columns, page.PrevCursor, 3)
+			assert.Equal(t, all[len(all)-6:len(all)-3], ids(page))
+			assert.True(t, page.HasNext)
+		})
+	}
+
+	t.Run("Where", func(t *testing.T) {
+		t.Parallel()
+
+		query := (&testEntItemQuery{db: db}).Where(func(s *sql.Selector) {
+			s.Where(sql.GT(s.C("score"), 1))
+		})
+		columns := []KeysetColumn{{Name: "id"}}
+
+		page, err := PaginateEnt(context.Background(), query, columns, valuesFunc, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3, 4}, ids(page))
+		assert.False(t, page.HasNext)
+		assert.Nil(t, page.NextCursor)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []KeysetColumn{{Name: "id"}}
+
+		_, err := PaginateEnt(context.Background(), &testEntItemQuery{db: db}, columns, valuesFunc, nil, 0)
+		require.Error(t, err)
+
+		_, err = PaginateEnt(context.Background(), &testEntItemQuery{db: db}, columns, valuesFunc, &KeysetCursor{Values: []any{1, 2}}, 10)
+		require.ErrorIs(t, err, ErrKeysetMismatch)
+
+		_, err = PaginateEnt(context.Background(), &testEntItemQuery{db: db}, nil, valuesFunc, nil, 10)
+		require.ErrorIs(t, err, ErrKeysetMismatch)
+	})
+}
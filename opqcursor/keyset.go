@@ -0,0 +1,222 @@
+package opqcursor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrKeysetMismatch is returned by KeysetWhere when the values of the cursor mismatch the columns.
+var ErrKeysetMismatch = errors.New("opqcursor: values of the keyset cursor mismatch the columns")
+
+// Direction is the direction of the keyset pagination.
+type Direction string
+
+const (
+	// DirectionNext pages to the rows after the cursor.
+	DirectionNext Direction = "next"
+	// DirectionPrev pages to the rows before the cursor.
+	DirectionPrev Direction = "prev"
+)
+
+// KeysetColumn is a column of the sort key in the keyset pagination.
+type KeysetColumn struct {
+	// Name is the name of the column, it is written into the SQL as is, therefore it must
+	// never come from the user input.
+	Name string
+	// Desc sorts the column in descending order.
+	Desc bool
+}
+
+// KeysetCursor is the position of the keyset (seek) pagination, it holds the values of the sort
+// key of the row to page from, the last row of the page for DirectionNext and the first row of
+// the page for DirectionPrev.
+type KeysetCursor struct {
+	Values    []any     `json:"values"`
+	Direction Direction `json:"direction"`
+}
+
+// UnmarshalJSON decodes the cursor, the integers in Values are decoded as int64 rather than
+// float64 to keep the precision, the other numbers are decoded as float64.
+func (c *KeysetCursor) UnmarshalJSON(data []byte) error {
+	var cursor struct {
+		Values    []any     `json:"values"`
+		Direction Direction `json:"direction"`
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&cursor)
+	if err != nil {
+		return err
+	}
+
+	for i, value := range cursor.Values {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+
+		integer, err := number.Int64()
+		if err == nil {
+			cursor.Values[i] = integer
+			continue
+		}
+
+		cursor.Values[i], err = number.Float64()
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Values = cursor.Values
+	c.Direction = cursor.Direction
+
+	return nil
+}
+
+// NextKeysetCursor returns the cursor to the page after page, built from the values of the sort
+// key of the last item returned by valuesFunc. The returned ok is false if page is empty.
+func NextKeysetCursor[T any](page []T, valuesFunc func(item T) []any) (cursor KeysetCursor, ok bool) {
+	if len(page) == 0 {
+		return cursor, false
+	}
+
+	return KeysetCursor{Values: valuesFunc(page[len(page)-1]), Direction: DirectionNext}, true
+}
+
+// PrevKeysetCursor returns the cursor to the page before page, built from the values of the sort
+// key of the first item returned by valuesFunc. The returned ok is false if page is empty.
+func PrevKeysetCursor[T any](page []T, valuesFunc func(item T) []any) (cursor KeysetCursor, ok bool) {
+	if len(page) == 0 {
+		return cursor, false
+	}
+
+	return KeysetCursor{Values: valuesFunc(page[0]), Direction: DirectionPrev}, true
+}
+
+// KeysetWhere returns the WHERE condition selecting the rows after the cursor in the order of
+// columns, or before the cursor for DirectionPrev, along with the arguments for the "?"
+// placeholders. The condition is a row value comparison such as "(a, b) > (?, ?)" if all of
+// columns are sorted in the same order, or the expanded form such as "(a > ?) OR (a = ? AND b < ?)"
+// otherwise. The last column is expected to be unique, such as the primary key, to break ties.
+func KeysetWhere(columns []KeysetColumn, cursor KeysetCursor) (string, []any, error) {
+	if len(columns) == 0 || len(columns) != len(cursor.Values) {
+		return "", nil, fmt.Errorf("%w: %d values for %d columns", ErrKeysetMismatch, len(cursor.Values), len(columns))
+	}
+
+	operators := make([]string, len(columns))
+	uniform := true
+
+	for i, column := range columns {
+		operators[i] = keysetOperator(column.Desc, cursor.Direction)
+		if operators[i] != operators[0] {
+			uniform = false
+		}
+	}
+
+	if len(columns) == 1 {
+		return columns[0].Name + " " + operators[0] + " ?", []any{cursor.Values[0]}, nil
+	}
+	if uniform {
+		names := make([]string, 0, len(columns))
+		for _, column := range columns {
+			names = append(names, column.Name)
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+
+		return "(" + strings.Join(names, ", ") + ") " + operators[0] + " (" + placeholders + ")", append([]any(nil), cursor.Values...), nil
+	}
+
+	conditions := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns)*(len(columns)+1)/2)
+
+	for i := range columns {
+		terms := make([]string, 0, i+1)
+
+		for j := 0; j < i; j++ {
+			terms = append(terms, columns[j].Name+" = ?")
+			args = append(args, cursor.Values[j])
+		}
+
+		terms = append(terms, columns[i].Name+" "+operators[i]+" ?")
+		args = append(args, cursor.Values[i])
+
+		conditions = append(conditions, "("+strings.Join(terms, " AND ")+")")
+	}
+
+	return strings.Join(conditions, " OR "), args, nil
+}
+
+func keysetOperator(desc bool, direction Direction) string {
+	if desc == (direction == DirectionPrev) {
+		return ">"
+	}
+
+	return "<"
+}
+
+// KeysetOrderBy returns the ORDER BY clause without the keyword to query the page in direction.
+// The order is reversed for DirectionPrev so that the rows nearest to the cursor are fetched
+// first, the caller has to reverse the fetched rows back, see ReverseKeysetPage.
+func KeysetOrderBy(columns []KeysetColumn, direction Direction) string {
+	orders := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		if column.Desc == (direction == DirectionPrev) {
+			orders = append(orders, column.Name+" ASC")
+		} else {
+			orders = append(orders, column.Name+" DESC")
+		}
+	}
+
+	return strings.Join(orders, ", ")
+}
+
+// ReverseKeysetPage reverses the rows fetched for DirectionPrev in place to restore the order
+// of columns, it does nothing for DirectionNext.
+func ReverseKeysetPage[T any](page []T, direction Direction) []T {
+	if direction != DirectionPrev {
+		return page
+	}
+
+	for i, j := 0, len(page)-1; i < j; i, j = i+1, j-1 {
+		page[i], page[j] = page[j], page[i]
+	}
+
+	return page
+}
+
+// MarshalKeyset encodes the keyset cursor with the unpadded URL-safe base64 encoding and
+// returns the cursor string, which can be put into the query strings as is. Use Codec to
+// protect the cursor from being forged.
+func MarshalKeyset(cursor KeysetCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// UnmarshalKeyset decodes the cursor string and returns the KeysetCursor struct.
+func UnmarshalKeyset(cursor string) (*KeysetCursor, error) {
+	var keyset KeysetCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &keyset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyset, nil
+}
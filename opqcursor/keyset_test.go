@@ -0,0 +1,150 @@
+package opqcursor
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysetWhere(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		columns  []KeysetColumn
+		cursor   KeysetCursor
+		expected string
+		args     []any
+	}{
+		{
+			name:     "SingleColumn",
+			columns:  []KeysetColumn{{Name: "id"}},
+			cursor:   KeysetCursor{Values: []any{1}, Direction: DirectionNext},
+			expected: "id > ?",
+			args:     []any{1},
+		},
+		{
+			name:     "SingleColumnPrev",
+			columns:  []KeysetColumn{{Name: "id"}},
+			cursor:   KeysetCursor{Values: []any{1}, Direction: DirectionPrev},
+			expected: "id < ?",
+			args:     []any{1},
+		},
+		{
+			name:     "Uniform",
+			columns:  []KeysetColumn{{Name: "created_at", Desc: true}, {Name: "id", Desc: true}},
+			cursor:   KeysetCursor{Values: []any{"2024-01-01", 2}, Direction: DirectionNext},
+			expected: "(created_at, id) < (?, ?)",
+			args:     []any{"2024-01-01", 2},
+		},
+		{
+			name:     "UniformPrev",
+			columns:  []KeysetColumn{{Name: "created_at", Desc: true}, {Name: "id", Desc: true}},
+			cursor:   KeysetCursor{Values: []any{"2024-01-01", 2}, Direction: DirectionPrev},
+			expected: "(created_at, id) > (?, ?)",
+			args:     []any{"2024-01-01", 2},
+		},
+		{
+			name:     "Mixed",
+			columns:  []KeysetColumn{{Name: "score", Desc: true}, {Name: "name"}, {Name: "id"}},
+			cursor:   KeysetCursor{Values: []any{10, "a", 3}, Direction: DirectionNext},
+			expected: "(score < ?) OR (score = ? AND name > ?) OR (score = ? AND name = ? AND id > ?)",
+			args:     []any{10, 10, "a", 10, "a", 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			where, args, err := KeysetWhere(tc.columns, tc.cursor)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, where)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+
+	t.Run("Mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := KeysetWhere([]KeysetColumn{{Name: "id"}}, KeysetCursor{Values: []any{1, 2}})
+		require.ErrorIs(t, err, ErrKeysetMismatch)
+
+		_, _, err = KeysetWhere(nil, KeysetCursor{})
+		require.ErrorIs(t, err, ErrKeysetMismatch)
+	})
+}
+
+func TestKeysetOrderBy(t *testing.T) {
+	t.Parallel()
+
+	columns := []KeysetColumn{{Name: "score", Desc: true}, {Name: "id"}}
+
+	assert.Equal(t, "score DESC, id ASC", KeysetOrderBy(columns, DirectionNext))
+	assert.Equal(t, "score ASC, id DESC", KeysetOrderBy(columns, DirectionPrev))
+}
+
+type keysetRow struct {
+	ID    int64
+	Score int64
+}
+
+func TestKeysetCursor(t *testing.T) {
+	t.Parallel()
+
+	page := []keysetRow{{ID: 9007199254740993, Score: 3}, {ID: 2, Score: 2}, {ID: 3, Score: 1}}
+	valuesFunc := func(row keysetRow) []any {
+		return []any{row.Score, row.ID}
+	}
+
+	next, ok := NextKeysetCursor(page, valuesFunc)
+	require.True(t, ok)
+	assert.Equal(t, KeysetCursor{Values: []any{int64(1), int64(3)}, Direction: DirectionNext}, next)
+
+	prev, ok := PrevKeysetCursor(page, valuesFunc)
+	require.True(t, ok)
+	assert.Equal(t, DirectionPrev, prev.Direction)
+
+	encoded, err := MarshalKeyset(prev)
+	require.NoError(t, err)
+
+	decoded, err := UnmarshalKeyset(encoded)
+	require.NoError(t, err)
+
+	// the integers are decoded without losing the precision.
+	assert.Equal(t, []any{int64(3), int64(9007199254740993)}, decoded.Values)
+	assert.Equal(t, DirectionPrev, decoded.Direction)
+
+	_, ok = NextKeysetCursor([]keysetRow{}, valuesFunc)
+	assert.False(t, ok)
+
+	_, err = UnmarshalKeyset("!")
+	require.Error(t, err)
+}
+
+func TestMarshalKeyset(t *testing.T) {
+	t.Parallel()
+
+	cursor := KeysetCursor{Values: []any{"a?b>c~", int64(1)}, Direction: DirectionNext}
+
+	encoded, err := MarshalKeyset(cursor)
+	require.NoError(t, err)
+	assert.NotContains(t, encoded, "=")
+
+	query, err := url.ParseQuery(url.Values{"after": {encoded}}.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, encoded, query.Get("after"))
+
+	decoded, err := UnmarshalKeyset(query.Get("after"))
+	require.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+}
+
+func TestReverseKeysetPage(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []int{1, 2, 3}, ReverseKeysetPage([]int{1, 2, 3}, DirectionNext))
+	assert.Equal(t, []int{3, 2, 1}, ReverseKeysetPage([]int{1, 2, 3}, DirectionPrev))
+}
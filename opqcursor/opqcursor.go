@@ -1,7 +1,6 @@
 package opqcursor
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"reflect"
 	"strings"
@@ -13,13 +12,16 @@ import (
 // Generally used in GraphQL based APIs to pass the cursor as a string. But the scenarios
 // are not limited to GraphQL only, you could use it in any API that requires a cursor.
 type OpaqueCursor[F any, S any] struct {
-	Filters F `json:"filters"`
-	Sorters S `json:"sorters"`
+	// Version is the version of the schema of the cursor, see WithVersion.
+	Version int `json:"version,omitempty"`
+	Filters F   `json:"filters"`
+	Sorters S   `json:"sorters"`
 }
 
 // IsValid checks if the cursor is valid or not. It checks if the sorters are valid or not.
 //
-// NOTICE: currently the sorters are checked for ASC and DESC only.
+// NOTICE: currently the sorters are checked for ASC and DESC only, see Validate for the
+// validation driven by the struct tags.
 func (oc OpaqueCursor[F, S]) IsValid() bool {
 	refValue := reflect.ValueOf(oc.Sorters)
 	if refValue.Kind() == reflect.Struct {
@@ -34,15 +36,17 @@ func (oc OpaqueCursor[F, S]) IsValid() bool {
 }
 
 // Unmarshal decodes the cursor string and returns the OpaqueCursor struct.
-func Unmarshal[Filters any, Sorters any](cursor string) (*OpaqueCursor[Filters, Sorters], error) {
+func Unmarshal[Filters any, Sorters any](cursor string, opts ...Option) (*OpaqueCursor[Filters, Sorters], error) {
 	var cursorData OpaqueCursor[Filters, Sorters]
 
-	base64Data, err := base64.StdEncoding.DecodeString(cursor)
+	options := newOptions(opts)
+
+	data, err := options.encoding.DecodeString(cursor)
 	if err != nil {
 		return &cursorData, err
 	}
 
-	err = json.Unmarshal(base64Data, &cursorData)
+	err = decodeCursor(data, options, &cursorData)
 	if err != nil {
 		return &cursorData, err
 	}
@@ -51,19 +55,21 @@ func Unmarshal[Filters any, Sorters any](cursor string) (*OpaqueCursor[Filters,
 }
 
 // UnmarshalWithDefaults decodes the cursor string and returns the OpaqueCursor struct with defaults additionally.
-func UnmarshalWithDefaults[Filters any, Sorters any](cursor string, defaults OpaqueCursor[Filters, Sorters]) (*OpaqueCursor[Filters, Sorters], error) {
+func UnmarshalWithDefaults[Filters any, Sorters any](cursor string, defaults OpaqueCursor[Filters, Sorters], opts ...Option) (*OpaqueCursor[Filters, Sorters], error) {
 	if cursor == "" {
 		return &defaults, nil
 	}
 
 	var cursorData OpaqueCursor[Filters, Sorters]
 
-	base64Data, err := base64.StdEncoding.DecodeString(cursor)
+	options := newOptions(opts)
+
+	data, err := options.encoding.DecodeString(cursor)
 	if err != nil {
 		return &cursorData, err
 	}
 
-	err = json.Unmarshal(base64Data, &cursorData)
+	err = decodeCursor(data, options, &cursorData)
 	if err != nil {
 		return &defaults, err
 	}
@@ -72,16 +78,27 @@ func UnmarshalWithDefaults[Filters any, Sorters any](cursor string, defaults Opa
 }
 
 // Marshal encodes the OpaqueCursor struct and returns the cursor string.
-func Marshal[Filters any, Sorters any](filters Filters, sorters Sorters) (string, error) {
-	cursorData := OpaqueCursor[Filters, Sorters]{
-		Filters: filters,
-		Sorters: sorters,
-	}
+func Marshal[Filters any, Sorters any](filters Filters, sorters Sorters, opts ...Option) (string, error) {
+	options := newOptions(opts)
 
-	cursor, err := json.Marshal(cursorData)
+	cursor, err := encodeCursor(OpaqueCursor[Filters, Sorters]{Filters: filters, Sorters: sorters}, options)
 	if err != nil {
 		return "{}", err
 	}
 
-	return base64.StdEncoding.EncodeToString(cursor), nil
+	return options.encoding.EncodeToString(cursor), nil
+}
+
+// encodeCursor validates the cursor if needed and encodes it as JSON with the version of options.
+func encodeCursor[Filters any, Sorters any](cursor OpaqueCursor[Filters, Sorters], options *options) ([]byte, error) {
+	cursor.Version = options.version
+
+	if options.validate {
+		err := cursor.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(cursor)
 }
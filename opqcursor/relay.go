@@ -0,0 +1,281 @@
+package opqcursor
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidConnectionArgs is returned when the arguments of the connection are invalid.
+var ErrInvalidConnectionArgs = errors.New("opqcursor: invalid connection arguments")
+
+// ConnectionArgs is the arguments of the connection fields defined by the Relay cursor
+// connections specification, see https://relay.dev/graphql/connections.htm.
+type ConnectionArgs struct {
+	First  *int    `json:"first,omitempty"`
+	After  *string `json:"after,omitempty"`
+	Last   *int    `json:"last,omitempty"`
+	Before *string `json:"before,omitempty"`
+}
+
+// Validate checks the arguments, first and last must be non-negative, no larger than
+// maxLimit, and must not be set at the same time as the specification strongly discourages.
+// maxLimit is not checked if it is not positive.
+func (a ConnectionArgs) Validate(maxLimit int) error {
+	if a.First != nil && a.Last != nil {
+		return fmt.Errorf("%w: first and last are set at the same time", ErrInvalidConnectionArgs)
+	}
+
+	err := validateConnectionLimit("first", a.First, maxLimit)
+	if err != nil {
+		return err
+	}
+
+	return validateConnectionLimit("last", a.Last, maxLimit)
+}
+
+func validateConnectionLimit(name string, limit *int, maxLimit int) error {
+	if limit == nil {
+		return nil
+	}
+	if *limit < 0 {
+		return fmt.Errorf("%w: %s must be non-negative, got %d", ErrInvalidConnectionArgs, name, *limit)
+	}
+	if maxLimit > 0 && *limit > maxLimit {
+		return fmt.Errorf("%w: %s must not exceed %d, got %d", ErrInvalidConnectionArgs, name, maxLimit, *limit)
+	}
+
+	return nil
+}
+
+// Keyset converts the arguments to the keyset cursor and the limit for the keyset
+// pagination, such as PaginateEnt, where the cursors of the edges are encoded by
+// MarshalKeyset. The cursor is nil for the first page, and the limit is defaultLimit if
+// neither first nor last is set. When last is set without before, the last items of all
+// are selected as the specification describes, the cursor then holds no values but
+// DirectionPrev, which PaginateEnt pages from the end.
+//
+// NOTICE: after and before cannot be set at the same time.
+func (a ConnectionArgs) Keyset(defaultLimit int) (*KeysetCursor, int, error) {
+	err := a.Validate(0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if a.After != nil && a.Before != nil {
+		return nil, 0, fmt.Errorf("%w: after and before are set at the same time", ErrInvalidConnectionArgs)
+	}
+
+	limit := defaultLimit
+	if a.First != nil {
+		limit = *a.First
+	}
+	if a.Last != nil {
+		limit = *a.Last
+	}
+
+	var encoded string
+
+	direction := DirectionNext
+
+	switch {
+	case a.After != nil:
+		encoded = *a.After
+	case a.Before != nil:
+		encoded = *a.Before
+		direction = DirectionPrev
+	case a.Last != nil:
+		return &KeysetCursor{Direction: DirectionPrev}, limit, nil
+	default:
+		return nil, limit, nil
+	}
+
+	cursor, err := UnmarshalKeyset(encoded)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrInvalidConnectionArgs, err)
+	}
+
+	cursor.Direction = direction
+
+	return cursor, limit, nil
+}
+
+// PageInfo is the information of the page of the connection.
+type PageInfo struct {
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	HasNextPage     bool    `json:"hasNextPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// Edge is an edge of the connection.
+type Edge[T any] struct {
+	Node   T      `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+// Connection is the connection defined by the Relay cursor connections specification.
+type Connection[T any] struct {
+	Edges    []Edge[T] `json:"edges"`
+	PageInfo PageInfo  `json:"pageInfo"`
+	// TotalCount is the total count of the items, it is nil if unavailable.
+	TotalCount *int `json:"totalCount,omitempty"`
+}
+
+// Nodes returns the nodes of the edges.
+func (c *Connection[T]) Nodes() []T {
+	nodes := make([]T, 0, len(c.Edges))
+	for _, edge := range c.Edges {
+		nodes = append(nodes, edge.Node)
+	}
+
+	return nodes
+}
+
+// ConnectionBuilder builds the Connection from a slice of the items.
+type ConnectionBuilder[T any] struct {
+	items           []T
+	cursorFunc      func(item T) (string, error)
+	args            *ConnectionArgs
+	hasPreviousPage bool
+	hasNextPage     bool
+	totalCount      *int
+}
+
+/*
+NewConnectionBuilder creates a builder of the connection of items, the cursors of the
+edges are returned by cursorFunc.
+
+For the items of a page queried already, such as EntPage, assign the page info:
+
+	connection, err := opqcursor.NewConnectionBuilder(page.Items, cursorFunc).
+		WithPageInfo(page.HasPrev, page.HasNext).
+		WithTotalCount(total).
+		Build()
+
+For all of the items, paginate them by the arguments:
+
+	connection, err := opqcursor.NewConnectionBuilder(items, cursorFunc).
+		WithArgs(args).
+		Build()
+*/
+func NewConnectionBuilder[T any](items []T, cursorFunc func(item T) (string, error)) *ConnectionBuilder[T] {
+	return &ConnectionBuilder[T]{
+		items:      items,
+		cursorFunc: cursorFunc,
+	}
+}
+
+// WithArgs paginates the items by the arguments as described by the specification, where the
+// items are treated as all of the items. The after and before cursors not found in the items
+// are ignored. The page info is computed from the position of the page, and the total count
+// is the count of the items unless assigned by WithTotalCount.
+func (b *ConnectionBuilder[T]) WithArgs(args ConnectionArgs) *ConnectionBuilder[T] {
+	b.args = &args
+	return b
+}
+
+// WithPageInfo assigns whether there are items before and after the items, it is ignored
+// if WithArgs is used.
+func (b *ConnectionBuilder[T]) WithPageInfo(hasPreviousPage, hasNextPage bool) *ConnectionBuilder[T] {
+	b.hasPreviousPage = hasPreviousPage
+	b.hasNextPage = hasNextPage
+
+	return b
+}
+
+// WithTotalCount assigns the total count of the items.
+func (b *ConnectionBuilder[T]) WithTotalCount(totalCount int) *ConnectionBuilder[T] {
+	b.totalCount = &totalCount
+	return b
+}
+
+// Build builds the connection, it returns the errors wrapping ErrInvalidConnectionArgs if
+// the arguments are invalid, or the error returned by the cursor function.
+func (b *ConnectionBuilder[T]) Build() (*Connection[T], error) {
+	connection := &Connection[T]{
+		Edges: make([]Edge[T], 0),
+		PageInfo: PageInfo{
+			HasPreviousPage: b.hasPreviousPage,
+			HasNextPage:     b.hasNextPage,
+		},
+		TotalCount: b.totalCount,
+	}
+
+	cursors := make([]string, 0, len(b.items))
+
+	for _, item := range b.items {
+		cursor, err := b.cursorFunc(item)
+		if err != nil {
+			return nil, err
+		}
+
+		cursors = append(cursors, cursor)
+	}
+
+	start, end := 0, len(b.items)
+
+	if b.args != nil {
+		err := b.args.Validate(0)
+		if err != nil {
+			return nil, err
+		}
+
+		start, end = b.slice(cursors)
+
+		connection.PageInfo.HasPreviousPage = start > 0
+		connection.PageInfo.HasNextPage = end < len(b.items)
+
+		if connection.TotalCount == nil {
+			totalCount := len(b.items)
+			connection.TotalCount = &totalCount
+		}
+	}
+
+	for i := start; i < end; i++ {
+		connection.Edges = append(connection.Edges, Edge[T]{Node: b.items[i], Cursor: cursors[i]})
+	}
+
+	if len(connection.Edges) > 0 {
+		startCursor := connection.Edges[0].Cursor
+		endCursor := connection.Edges[len(connection.Edges)-1].Cursor
+
+		connection.PageInfo.StartCursor = &startCursor
+		connection.PageInfo.EndCursor = &endCursor
+	}
+
+	return connection, nil
+}
+
+// slice returns the range of the page in the items selected by the arguments.
+func (b *ConnectionBuilder[T]) slice(cursors []string) (int, int) {
+	start, end := 0, len(cursors)
+
+	for i, cursor := range cursors {
+		if b.args.After != nil && cursor == *b.args.After {
+			start = i + 1
+		}
+		if b.args.Before != nil && cursor == *b.args.Before {
+			end = i
+		}
+	}
+
+	if end < start {
+		end = start
+	}
+	if b.args.First != nil && end-start > *b.args.First {
+		end = start + *b.args.First
+	}
+	if b.args.Last != nil && end-start > *b.args.Last {
+		start = end - *b.args.Last
+	}
+
+	return start, end
+}
+
+// KeysetCursorFunc returns the cursor function for NewConnectionBuilder encoding the values
+// of the sort key of the items returned by valuesFunc with MarshalKeyset, which can be
+// converted back by ConnectionArgs.Keyset.
+func KeysetCursorFunc[T any](valuesFunc func(item T) []any) func(item T) (string, error) {
+	return func(item T) (string, error) {
+		return MarshalKeyset(KeysetCursor{Values: valuesFunc(item)})
+	}
+}
@@ -0,0 +1,203 @@
+package opqcursor
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionArgs_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ConnectionArgs{}.Validate(10))
+	require.NoError(t, ConnectionArgs{First: lo.ToPtr(10), After: lo.ToPtr("a")}.Validate(10))
+	require.NoError(t, ConnectionArgs{Last: lo.ToPtr(100)}.Validate(0))
+
+	for _, args := range []ConnectionArgs{
+		{First: lo.ToPtr(-1)},
+		{Last: lo.ToPtr(-1)},
+		{First: lo.ToPtr(11)},
+		{First: lo.ToPtr(1), Last: lo.ToPtr(1)},
+	} {
+		require.ErrorIs(t, args.Validate(10), ErrInvalidConnectionArgs)
+	}
+}
+
+func TestConnectionArgs_Keyset(t *testing.T) {
+	t.Parallel()
+
+	cursorFunc := KeysetCursorFunc(func(item keysetRow) []any {
+		return []any{item.Score, item.ID}
+	})
+
+	encoded, err := cursorFunc(keysetRow{ID: 2, Score: 1})
+	require.NoError(t, err)
+
+	cursor, limit, err := ConnectionArgs{}.Keyset(20)
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+	assert.Equal(t, 20, limit)
+
+	cursor, limit, err = ConnectionArgs{First: lo.ToPtr(5), After: &encoded}.Keyset(20)
+	require.NoError(t, err)
+	assert.Equal(t, &KeysetCursor{Values: []any{int64(1), int64(2)}, Direction: DirectionNext}, cursor)
+	assert.Equal(t, 5, limit)
+
+	cursor, limit, err = ConnectionArgs{Last: lo.ToPtr(3), Before: &encoded}.Keyset(20)
+	require.NoError(t, err)
+	assert.Equal(t, &KeysetCursor{Values: []any{int64(1), int64(2)}, Direction: DirectionPrev}, cursor)
+	assert.Equal(t, 3, limit)
+
+	cursor, limit, err = ConnectionArgs{Last: lo.ToPtr(3)}.Keyset(20)
+	require.NoError(t, err)
+	assert.Equal(t, &KeysetCursor{Direction: DirectionPrev}, cursor)
+	assert.Equal(t, 3, limit)
+
+	for _, args := range []ConnectionArgs{
+		{After: &encoded, Before: &encoded},
+		{After: lo.ToPtr("!")},
+		{First: lo.ToPtr(-1)},
+	} {
+		_, _, err = args.Keyset(20)
+		require.ErrorIs(t, err, ErrInvalidConnectionArgs)
+	}
+}
+
+func TestConnectionBuilder(t *testing.T) {
+	t.Parallel()
+
+	items := []int{1, 2, 3, 4, 5}
+	cursorFunc := func(item int) (string, error) {
+		return strconv.Itoa(item), nil
+	}
+
+	testCases := []struct {
+		name            string
+		args            ConnectionArgs
+		nodes           []int
+		hasPreviousPage bool
+		hasNextPage     bool
+	}{
+		{
+			name:  "All",
+			nodes: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:        "First",
+			args:        ConnectionArgs{First: lo.ToPtr(2)},
+			nodes:       []int{1, 2},
+			hasNextPage: true,
+		},
+		{
+			name:            "FirstAfter",
+			args:            ConnectionArgs{First: lo.ToPtr(2), After: lo.ToPtr("2")},
+			nodes:           []int{3, 4},
+			hasPreviousPage: true,
+			hasNextPage:     true,
+		},
+		{
+			name:            "Last",
+			args:            ConnectionArgs{Last: lo.ToPtr(2)},
+			nodes:           []int{4, 5},
+			hasPreviousPage: true,
+		},
+		{
+			name:            "LastBefore",
+			args:            ConnectionArgs{Last: lo.ToPtr(2), Before: lo.ToPtr("4")},
+			nodes:           []int{2, 3},
+			hasPreviousPage: true,
+			hasNextPage:     true,
+		},
+		{
+			name:            "AfterBefore",
+			args:            ConnectionArgs{After: lo.ToPtr("1"), Before: lo.ToPtr("5")},
+			nodes:           []int{2, 3, 4},
+			hasPreviousPage: true,
+			hasNextPage:     true,
+		},
+		{
+			name:  "UnknownCursor",
+			args:  ConnectionArgs{After: lo.ToPtr("6")},
+			nodes: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:            "Empty",
+			args:            ConnectionArgs{After: lo.ToPtr("5")},
+			nodes:           []int{},
+			hasPreviousPage: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			connection, err := NewConnectionBuilder(items, cursorFunc).WithArgs(tc.args).Build()
+			require.NoError(t, err)
+			assert.Equal(t, tc.nodes, connection.Nodes())
+			assert.Equal(t, tc.hasPreviousPage, connection.PageInfo.HasPreviousPage)
+			assert.Equal(t, tc.hasNextPage, connection.PageInfo.HasNextPage)
+			require.NotNil(t, connection.TotalCount)
+			assert.Equal(t, len(items), *connection.TotalCount)
+
+			if len(tc.nodes) == 0 {
+				assert.Nil(t, connection.PageInfo.StartCursor)
+				assert.Nil(t, connection.PageInfo.EndCursor)
+
+				return
+			}
+
+			require.NotNil(t, connection.PageInfo.StartCursor)
+			require.NotNil(t, connection.PageInfo.EndCursor)
+			assert.Equal(t, strconv.Itoa(tc.nodes[0]), *connection.PageInfo.StartCursor)
+			assert.Equal(t, strconv.Itoa(tc.nodes[len(tc.nodes)-1]), *connection.PageInfo.EndCursor)
+		})
+	}
+
+	t.Run("PageInfo", func(t *testing.T) {
+		t.Parallel()
+
+		connection, err := NewConnectionBuilder(items[1:3], cursorFunc).
+			WithPageInfo(true, false).
+			WithTotalCount(10).
+			Build()
+		require.NoError(t, err)
+
+		data, err := json.Marshal(connection)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"edges": [{"node": 2, "cursor": "2"}, {"node": 3, "cursor": "3"}],
+			"pageInfo": {"hasPreviousPage": true, "hasNextPage": false, "startCursor": "2", "endCursor": "3"},
+			"totalCount": 10
+		}`, string(data))
+
+		connection, err = NewConnectionBuilder([]int{}, cursorFunc).Build()
+		require.NoError(t, err)
+
+		data, err = json.Marshal(connection)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"edges": [],
+			"pageInfo": {"hasPreviousPage": false, "hasNextPage": false, "startCursor": null, "endCursor": null}
+		}`, string(data))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewConnectionBuilder(items, cursorFunc).WithArgs(ConnectionArgs{First: lo.ToPtr(-1)}).Build()
+		require.ErrorIs(t, err, ErrInvalidConnectionArgs)
+
+		expectedErr := errors.New("failed")
+
+		_, err = NewConnectionBuilder(items, func(int) (string, error) {
+			return "", expectedErr
+		}).Build()
+		require.ErrorIs(t, err, expectedErr)
+	})
+}
@@ -0,0 +1,275 @@
+package opqcursor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+var (
+	// ErrUnsupportedVersion is returned when the version of the cursor is newer than the
+	// current version, or older without a migration, see WithVersion and WithMigration.
+	ErrUnsupportedVersion = errors.New("opqcursor: unsupported cursor version")
+	// ErrInvalidCursor is returned when the cursor violates the rules of the struct tags, see
+	// OpaqueCursor.Validate.
+	ErrInvalidCursor = errors.New("opqcursor: invalid cursor")
+)
+
+type options struct {
+	version    int
+	migrations map[int]func(data []byte) ([]byte, error)
+	validate   bool
+	encoding   *base64.Encoding
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		migrations: make(map[int]func(data []byte) ([]byte, error)),
+		encoding:   base64.StdEncoding,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// Option configures the encoding and decoding of the OpaqueCursor.
+type Option func(*options)
+
+// WithVersion assigns the current version of the schema of the cursors, defaults to 0.
+// The cursors are encoded with the version, and the cursors of the older versions are
+// migrated by the migrations assigned by WithMigration, or rejected with
+// ErrUnsupportedVersion if there is no migration. The cursors of the newer versions are
+// always rejected. The cursors encoded before versioning are of version 0.
+func WithVersion(version int) Option {
+	return func(o *options) {
+		o.version = version
+	}
+}
+
+/*
+WithMigration assigns the migration of the cursors from version from to version from+1,
+migrate receives the JSON of the cursor and returns the migrated JSON. The migrations are
+chained to migrate the cursors to the current version.
+
+	opqcursor.Unmarshal[Filters, Sorters](cursor,
+		opqcursor.WithVersion(2),
+		opqcursor.WithMigration(0, renameTenantFilter),
+		opqcursor.WithMigration(1, dropLegacySorter),
+	)
+*/
+func WithMigration(from int, migrate func(data []byte) ([]byte, error)) Option {
+	return func(o *options) {
+		o.migrations[from] = migrate
+	}
+}
+
+// WithValidation validates the cursors with OpaqueCursor.Validate when encoding and
+// decoding, and rejects the unknown fields when decoding, the errors wrap ErrInvalidCursor
+// and ErrMalformedCursor respectively.
+func WithValidation() Option {
+	return func(o *options) {
+		o.validate = true
+	}
+}
+
+// WithURLEncoding encodes the cursors with the unpadded URL-safe base64 encoding instead of
+// the standard base64 encoding, so that the cursors can be put into the query strings as is.
+// The cursors must be decoded with the same option. Codec always encodes the cursors
+// URL-safe, the option is ignored by MarshalWithCodec and UnmarshalWithCodec.
+func WithURLEncoding() Option {
+	return func(o *options) {
+		o.encoding = base64.RawURLEncoding
+	}
+}
+
+// decodeCursor migrates the cursor in JSON to the current version of options and decodes it
+// into cursor, then validates it if needed.
+func decodeCursor[Filters any, Sorters any](data []byte, options *options, cursor *OpaqueCursor[Filters, Sorters]) error {
+	data, err := migrateCursor(data, options)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if options.validate {
+		decoder.DisallowUnknownFields()
+	}
+
+	err = decoder.Decode(cursor)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedCursor, err)
+	}
+
+	cursor.Version = options.version
+
+	if options.validate {
+		return cursor.Validate()
+	}
+
+	return nil
+}
+
+func migrateCursor(data []byte, options *options) ([]byte, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+
+	err := json.Unmarshal(data, &header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedCursor, err)
+	}
+	if header.Version > options.version {
+		return nil, fmt.Errorf("%w: version %d is newer than %d", ErrUnsupportedVersion, header.Version, options.version)
+	}
+
+	for version := header.Version; version < options.version; version++ {
+		migrate, ok := options.migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: no migration from version %d", ErrUnsupportedVersion, version)
+		}
+
+		data, err = migrate(data)
+		if err != nil {
+			return nil, fmt.Errorf("opqcursor: failed to migrate cursor from version %d: %w", version, err)
+		}
+	}
+
+	return data, nil
+}
+
+/*
+Validate validates the filters and the sorters by the rules in the opqcursor struct tags
+of their fields, the rules are separated by commas:
+
+  - required: the field must not be the zero value.
+  - oneof=a b c: the field must be one of the values separated by spaces if it is not the
+    zero value, each of the elements is checked for slices.
+
+The string fields of the sorters without the oneof rule must be either ASC or DESC case
+insensitively if they are not empty.
+
+	type Filters struct {
+		TenantID string   `json:"tenantId" opqcursor:"required"`
+		Statuses []string `json:"statuses" opqcursor:"oneof=active archived"`
+	}
+
+	type Sorters struct {
+		CreatedAt string `json:"createdAt"`
+	}
+
+It returns the errors wrapping ErrInvalidCursor.
+*/
+func (oc OpaqueCursor[F, S]) Validate() error {
+	err := validateFields("filters", reflect.ValueOf(oc.Filters), false)
+	if err != nil {
+		return err
+	}
+
+	return validateFields("sorters", reflect.ValueOf(oc.Sorters), true)
+}
+
+func validateFields(path string, value reflect.Value, sorters bool) error {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		err := validateField(path+"."+name, field.Tag.Get("opqcursor"), value.Field(i), sorters)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateField(path string, tag string, value reflect.Value, sorter bool) error {
+	var (
+		required bool
+		oneOf    []string
+	)
+
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+
+		switch {
+		case rule == "":
+		case rule == "required":
+			required = true
+		case strings.HasPrefix(rule, "oneof="):
+			oneOf = strings.Fields(strings.TrimPrefix(rule, "oneof="))
+		default:
+			return fmt.Errorf("opqcursor: unknown rule %q of %s", rule, path)
+		}
+	}
+
+	if value.IsZero() {
+		if required {
+			return fmt.Errorf("%w: %s is required", ErrInvalidCursor, path)
+		}
+
+		return nil
+	}
+
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	if oneOf == nil {
+		if sorter && value.Kind() == reflect.String && !lo.Contains([]string{"ASC", "DESC"}, strings.ToUpper(value.String())) {
+			return fmt.Errorf("%w: %s must be ASC or DESC, got %q", ErrInvalidCursor, path, value.String())
+		}
+
+		return nil
+	}
+
+	values := []reflect.Value{value}
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		values = make([]reflect.Value, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			values = append(values, value.Index(i))
+		}
+	}
+
+	for _, v := range values {
+		str := fmt.Sprint(v.Interface())
+		if !lo.Contains(oneOf, str) {
+			return fmt.Errorf("%w: %s must be one of %v, got %q", ErrInvalidCursor, path, oneOf, str)
+		}
+	}
+
+	return nil
+}
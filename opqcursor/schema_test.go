@@ -0,0 +1,180 @@
+package opqcursor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testValidatedFilters struct {
+	TenantID string   `json:"tenantId" opqcursor:"required"`
+	Statuses []string `json:"statuses,omitempty" opqcursor:"oneof=active archived"`
+	Kind     *int     `json:"kind,omitempty" opqcursor:"oneof=1 2"`
+}
+
+type testValidatedSorters struct {
+	CreatedAt string `json:"createdAt"`
+	Name      string `json:"name,omitempty" opqcursor:"oneof=ASC"`
+}
+
+func TestOpaqueCursor_Validate(t *testing.T) {
+	t.Parallel()
+
+	kind := 2
+	invalidKind := 3
+
+	valid := []OpaqueCursor[testValidatedFilters, testValidatedSorters]{
+		{Filters: testValidatedFilters{TenantID: "tenant"}},
+		{
+			Filters: testValidatedFilters{TenantID: "tenant", Statuses: []string{"active", "archived"}, Kind: &kind},
+			Sorters: testValidatedSorters{CreatedAt: "desc", Name: "ASC"},
+		},
+	}
+	for _, cursor := range valid {
+		require.NoError(t, cursor.Validate())
+	}
+
+	invalid := map[string]OpaqueCursor[testValidatedFilters, testValidatedSorters]{
+		"filters.tenantId":  {},
+		"filters.statuses":  {Filters: testValidatedFilters{TenantID: "tenant", Statuses: []string{"active", "deleted"}}},
+		"filters.kind":      {Filters: testValidatedFilters{TenantID: "tenant", Kind: &invalidKind}},
+		"sorters.createdAt": {Filters: testValidatedFilters{TenantID: "tenant"}, Sorters: testValidatedSorters{CreatedAt: "RANDOM"}},
+		"sorters.name":      {Filters: testValidatedFilters{TenantID: "tenant"}, Sorters: testValidatedSorters{Name: "DESC"}},
+	}
+	for path, cursor := range invalid {
+		err := cursor.Validate()
+		require.ErrorIs(t, err, ErrInvalidCursor, path)
+		assert.Contains(t, err.Error(), path)
+	}
+
+	var unknownRule OpaqueCursor[struct {
+		ID string `opqcursor:"unique"`
+	}, testSorters]
+
+	unknownRule.Filters.ID = "id"
+
+	err := unknownRule.Validate()
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestWithValidation(t *testing.T) {
+	t.Parallel()
+
+	_, err := Marshal(testValidatedFilters{}, testValidatedSorters{}, WithValidation())
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	cursor, err := Marshal(testValidatedFilters{TenantID: "tenant"}, testValidatedSorters{CreatedAt: "RANDOM"})
+	require.NoError(t, err)
+
+	// decodes silently without validation.
+	_, err = Unmarshal[testValidatedFilters, testValidatedSorters](cursor)
+	require.NoError(t, err)
+
+	_, err = Unmarshal[testValidatedFilters, testValidatedSorters](cursor, WithValidation())
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	unknownField := base64.StdEncoding.EncodeToString([]byte(`{"filters":{"tenantId":"tenant","admin":true},"sorters":{}}`))
+
+	_, err = Unmarshal[testValidatedFilters, testValidatedSorters](unknownField)
+	require.NoError(t, err)
+
+	_, err = Unmarshal[testValidatedFilters, testValidatedSorters](unknownField, WithValidation())
+	require.ErrorIs(t, err, ErrMalformedCursor)
+
+	codec, err := NewCodec(CodecModeSign, Key{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)})
+	require.NoError(t, err)
+
+	codecCursor, err := MarshalWithCodec(codec, testValidatedFilters{TenantID: "tenant"}, testValidatedSorters{CreatedAt: "RANDOM"})
+	require.NoError(t, err)
+
+	_, err = UnmarshalWithCodec[testValidatedFilters, testValidatedSorters](codec, codecCursor, WithValidation())
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestWithVersion(t *testing.T) {
+	t.Parallel()
+
+	type filtersV0 struct {
+		Tenant string `json:"tenant"`
+	}
+
+	// version 0 to 1 renames tenant to tenantId, version 1 to 2 adds the statuses.
+	migrations := []Option{
+		WithMigration(0, func(data []byte) ([]byte, error) {
+			var cursor map[string]map[string]any
+
+			err := json.Unmarshal(data, &cursor)
+			if err != nil {
+				return nil, err
+			}
+
+			cursor["filters"]["tenantId"] = cursor["filters"]["tenant"]
+			delete(cursor["filters"], "tenant")
+
+			return json.Marshal(cursor)
+		}),
+		WithMigration(1, func(data []byte) ([]byte, error) {
+			return bytes.Replace(data, []byte(`"filters":{`), []byte(`"filters":{"statuses":["active"],`), 1), nil
+		}),
+	}
+
+	legacy, err := Marshal(filtersV0{Tenant: "tenant"}, testValidatedSorters{})
+	require.NoError(t, err)
+
+	migrated, err := Unmarshal[testValidatedFilters, testValidatedSorters](legacy, append(migrations, WithVersion(2), WithValidation())...)
+	require.NoError(t, err)
+	assert.Equal(t, 2, migrated.Version)
+	assert.Equal(t, testValidatedFilters{TenantID: "tenant", Statuses: []string{"active"}}, migrated.Filters)
+
+	_, err = Unmarshal[testValidatedFilters, testValidatedSorters](legacy, WithVersion(2), migrations[1])
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	current, err := Marshal(testValidatedFilters{TenantID: "tenant"}, testValidatedSorters{}, WithVersion(2))
+	require.NoError(t, err)
+
+	decoded, err := Unmarshal[testValidatedFilters, testValidatedSorters](current, WithVersion(2))
+	require.NoError(t, err)
+	assert.Equal(t, 2, decoded.Version)
+	assert.Equal(t, "tenant", decoded.Filters.TenantID)
+
+	// the cursors of the newer versions are rejected.
+	_, err = Unmarshal[testValidatedFilters, testValidatedSorters](current, WithVersion(1))
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	_, err = Unmarshal[testValidatedFilters, testValidatedSorters](current)
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	defaults := OpaqueCursor[testValidatedFilters, testValidatedSorters]{Filters: testValidatedFilters{TenantID: "default"}}
+
+	decoded, err = UnmarshalWithDefaults(current, defaults, WithVersion(1))
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+	assert.Equal(t, "default", decoded.Filters.TenantID)
+}
+
+func TestWithURLEncoding(t *testing.T) {
+	t.Parallel()
+
+	// the filter encodes to "+" and "/" with the standard base64 encoding.
+	filters := testFilters{TenantID: "???>>>"}
+
+	cursor, err := Marshal(filters, testSorters{CreatedAt: "DESC"})
+	require.NoError(t, err)
+	assert.True(t, strings.ContainsAny(cursor, "+/="))
+
+	urlCursor, err := Marshal(filters, testSorters{CreatedAt: "DESC"}, WithURLEncoding())
+	require.NoError(t, err)
+	assert.False(t, strings.ContainsAny(urlCursor, "+/="))
+
+	decoded, err := Unmarshal[testFilters, testSorters](urlCursor, WithURLEncoding())
+	require.NoError(t, err)
+	assert.Equal(t, filters, decoded.Filters)
+
+	_, err = Unmarshal[testFilters, testSorters](cursor, WithURLEncoding())
+	require.Error(t, err)
+}
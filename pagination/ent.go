@@ -0,0 +1,16 @@
+package pagination
+
+// EntQuery is the query builder generated by ent, such as *ent.UserQuery.
+type EntQuery[Q any] interface {
+	Offset(offset int) Q
+	Limit(limit int) Q
+}
+
+// ApplyEnt applies the offset and the limit of the pagination to the ent query builder.
+//
+//	users, err := pagination.ApplyEnt(client.User.Query(), pa).All(ctx)
+//
+// NOTICE: Make sure that pa.Valid() returns true.
+func ApplyEnt[Q EntQuery[Q]](query Q, pa Pagination) Q {
+	return query.Offset(int(pa.Offset())).Limit(int(pa.Limit()))
+}
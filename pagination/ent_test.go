@@ -0,0 +1,34 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEntQuery struct {
+	offset int
+	limit  int
+}
+
+func (q *testEntQuery) Offset(offset int) *testEntQuery {
+	q.offset = offset
+	return q
+}
+
+func (q *testEntQuery) Limit(limit int) *testEntQuery {
+	q.limit = limit
+	return q
+}
+
+func TestApplyEnt(t *testing.T) {
+	assert := assert.New(t)
+
+	query := ApplyEnt(&testEntQuery{}, New(6, 20, 101))
+	assert.Equal(100, query.offset)
+	assert.Equal(1, query.limit)
+
+	query = ApplyEnt(&testEntQuery{}, New(2, 20, 101))
+	assert.Equal(20, query.offset)
+	assert.Equal(20, query.limit)
+}
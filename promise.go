@@ -0,0 +1,137 @@
+package xo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sourcegraph/conc/panics"
+)
+
+// ErrAllFailed is returned by Any when none of the functions succeeds.
+var ErrAllFailed = errors.New("xo: all of the functions failed")
+
+// SettledResult is the result of a function run by AllSettled.
+type SettledResult[T any] struct {
+	// Value is the value returned by the function.
+	Value T
+	// Err is the error returned by the function, the panics are passed as *panics.ErrRecovered.
+	Err error
+}
+
+// tryFunc calls fn, the panics of fn are recovered and returned as *panics.ErrRecovered.
+func tryFunc[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) (value T, err error) {
+	recovered := panics.Try(func() {
+		value, err = fn(ctx)
+	})
+	if recovered != nil {
+		return value, recovered.AsError()
+	}
+
+	return value, err
+}
+
+// All runs funcs concurrently and returns their values in the order of funcs once all of
+// them succeed. Once any of funcs returns an error or panics, the ctx passed to the other
+// funcs is cancelled and the error is returned without waiting for the other funcs to
+// return. The panics are returned as *panics.ErrRecovered.
+func All[T any](ctx context.Context, funcs ...func(ctx context.Context) (T, error)) ([]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	values := make([]T, len(funcs))
+	// buffered so that the funcs are not blocked on reporting after All returns.
+	errs := make(chan error, len(funcs))
+
+	for i, fn := range funcs {
+		go func() {
+			value, err := tryFunc(ctx, fn)
+			if err == nil {
+				values[i] = value
+			}
+
+			errs <- err
+		}()
+	}
+
+	for range funcs {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-errs:
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// Any runs funcs concurrently and returns the value of the first one to succeed along with
+// its index, the ctx passed to the other funcs is cancelled then without waiting for them to
+// return. If none of funcs succeeds, the returned error wraps ErrAllFailed and the errors
+// of funcs, and index is -1. The panics are treated as errors of *panics.ErrRecovered.
+func Any[T any](ctx context.Context, funcs ...func(ctx context.Context) (T, error)) (value T, index int, err error) {
+	type result struct {
+		value T
+		index int
+		err   error
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// buffered so that the funcs are not blocked on reporting after Any returns.
+	results := make(chan result, len(funcs))
+
+	for i, fn := range funcs {
+		go func() {
+			value, err := tryFunc(ctx, fn)
+			results <- result{value: value, index: i, err: err}
+		}()
+	}
+
+	errs := make([]error, len(funcs))
+
+	for range funcs {
+		select {
+		case <-ctx.Done():
+			return value, -1, ctx.Err()
+		case r := <-results:
+			if r.err == nil {
+				return r.value, r.index, nil
+			}
+
+			errs[r.index] = r.err
+		}
+	}
+	if len(errs) == 0 {
+		return value, -1, ErrAllFailed
+	}
+
+	return value, -1, fmt.Errorf("%w: %w", ErrAllFailed, errors.Join(errs...))
+}
+
+// AllSettled runs funcs concurrently, waits for all of them to return, and returns their
+// results in the order of funcs. The panics are returned as *panics.ErrRecovered.
+func AllSettled[T any](ctx context.Context, funcs ...func(ctx context.Context) (T, error)) []SettledResult[T] {
+	results := make([]SettledResult[T], len(funcs))
+
+	var wg sync.WaitGroup
+
+	for i, fn := range funcs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i].Value, results[i].Err = tryFunc(ctx, fn)
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,178 @@
+package xo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/conc/panics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func returns[T any](value T, err error, delay time.Duration) func(ctx context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		select {
+		case <-ctx.Done():
+			var empty T
+			return empty, ctx.Err()
+		case <-time.After(delay):
+			return value, err
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ok", func(t *testing.T) {
+		t.Parallel()
+
+		values, err := All(context.Background(),
+			returns(1, nil, time.Millisecond*20),
+			returns(2, nil, 0),
+			returns(3, nil, time.Millisecond*10),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, values)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		values, err := All[int](context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("failed")
+		cancelled := make(chan struct{})
+
+		values, err := All(context.Background(),
+			func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				close(cancelled)
+
+				return 0, ctx.Err()
+			},
+			returns(0, expectedErr, 0),
+		)
+		require.ErrorIs(t, err, expectedErr)
+		assert.Nil(t, values)
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			require.FailNow(t, "other functions not cancelled")
+		}
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := All(context.Background(),
+			returns(1, nil, time.Hour),
+			func(ctx context.Context) (int, error) {
+				panic("panic")
+			},
+		)
+
+		var recovered *panics.ErrRecovered
+
+		require.ErrorAs(t, err, &recovered)
+		assert.Equal(t, "panic", recovered.Value)
+	})
+}
+
+func TestAny(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FirstSuccess", func(t *testing.T) {
+		t.Parallel()
+
+		value, index, err := Any(context.Background(),
+			returns(0, errors.New("failed"), 0),
+			returns(2, nil, time.Millisecond*10),
+			returns(3, nil, time.Hour),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+		assert.Equal(t, 1, index)
+	})
+
+	t.Run("AllFailed", func(t *testing.T) {
+		t.Parallel()
+
+		err1 := errors.New("failed 1")
+		err2 := errors.New("failed 2")
+
+		_, index, err := Any(context.Background(),
+			returns(0, err1, time.Millisecond*10),
+			returns(0, err2, 0),
+			func(ctx context.Context) (int, error) {
+				panic("panic")
+			},
+		)
+		assert.Equal(t, -1, index)
+		require.ErrorIs(t, err, ErrAllFailed)
+		require.ErrorIs(t, err, err1)
+		require.ErrorIs(t, err, err2)
+
+		var recovered *panics.ErrRecovered
+
+		require.ErrorAs(t, err, &recovered)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		_, index, err := Any[int](context.Background())
+		assert.Equal(t, -1, index)
+		require.ErrorIs(t, err, ErrAllFailed)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, index, err := Any(ctx, func(ctx context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		assert.Equal(t, -1, index)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestAllSettled(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("failed")
+
+	results := AllSettled(context.Background(),
+		returns("a", nil, time.Millisecond*10),
+		returns("", expectedErr, 0),
+		func(ctx context.Context) (string, error) {
+			panic("panic")
+		},
+	)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "a", results[0].Value)
+	require.NoError(t, results[0].Err)
+	require.ErrorIs(t, results[1].Err, expectedErr)
+
+	var recovered *panics.ErrRecovered
+
+	require.ErrorAs(t, results[2].Err, &recovered)
+	assert.Equal(t, "panic", recovered.Value)
+}
@@ -1,5 +1,11 @@
 package xo
 
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
 func Race2[T0 any, T1 any](src1 chan T0, src2 chan T1) (T0, T1) {
 	var empty0 T0
 	var empty1 T1
@@ -89,3 +95,72 @@ func Race6[T0 any, T1 any, T2 any, T3 any, T4 any, T5 any](src1 chan T0, src2 ch
 		return empty0, empty1, empty2, empty3, empty4, v
 	}
 }
+
+// ErrNoCandidates is returned by Race and RaceFunc when there is no channel or function
+// to race.
+var ErrNoCandidates = errors.New("xo: no candidates to race")
+
+// Race receives from srcs and returns the first value received along with the index of
+// the channel it is received from. The ok is false if the channel at index is closed. If
+// ctx is done before any of srcs sends, index is -1 and the error is ctx.Err(), and if srcs
+// is empty, Race returns immediately with index -1 and ErrNoCandidates.
+func Race[T any](ctx context.Context, srcs ...<-chan T) (value T, index int, ok bool, err error) {
+	if len(srcs) == 0 {
+		return value, -1, false, ErrNoCandidates
+	}
+
+	cases := make([]reflect.SelectCase, 0, len(srcs)+1)
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+
+	for _, src := range srcs {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(src)})
+	}
+
+	chosen, received, ok := reflect.Select(cases)
+	if chosen == 0 {
+		return value, -1, false, ctx.Err()
+	}
+	if ok {
+		value, _ = received.Interface().(T)
+	}
+
+	return value, chosen - 1, ok, nil
+}
+
+// RaceFunc runs funcs concurrently and returns the result of the first one to return along
+// with its index, the ctx passed to the other funcs is cancelled then. RaceFunc doesn't wait
+// for the other funcs to return. The panics of funcs are recovered and returned as
+// *panics.ErrRecovered. If ctx is done before any of funcs returns, index is -1 and the
+// error is ctx.Err(), and if funcs is empty, RaceFunc returns immediately with index -1
+// and ErrNoCandidates.
+func RaceFunc[T any](ctx context.Context, funcs ...func(ctx context.Context) (T, error)) (value T, index int, err error) {
+	if len(funcs) == 0 {
+		return value, -1, ErrNoCandidates
+	}
+
+	type result struct {
+		value T
+		index int
+		err   error
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// buffered so that the losers are not blocked on sending their results.
+	results := make(chan result, len(funcs))
+
+	for i, fn := range funcs {
+		go func() {
+			value, err := tryFunc(ctx, fn)
+			results <- result{value: value, index: i, err: err}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		return value, -1, ctx.Err()
+	case r := <-results:
+		return r.value, r.index, r.err
+	}
+}
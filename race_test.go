@@ -1,8 +1,14 @@
 package xo
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/sourcegraph/conc/panics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRace2(t *testing.T) {
@@ -164,3 +170,147 @@ func TestRace6(t *testing.T) {
 		t.Errorf("expected v6=\"\", got %s", v6)
 	}
 }
+
+func TestRace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Winner", func(t *testing.T) {
+		t.Parallel()
+
+		src1 := make(chan int)
+		src2 := make(chan int, 1)
+		src3 := make(chan int)
+
+		src2 <- 0
+
+		value, index, ok, err := Race(context.Background(), src1, src2, src3)
+		require.NoError(t, err)
+		assert.Equal(t, 0, value)
+		assert.Equal(t, 1, index)
+		assert.True(t, ok)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		t.Parallel()
+
+		src1 := make(chan string)
+		src2 := make(chan string)
+
+		close(src2)
+
+		value, index, ok, err := Race(context.Background(), src1, src2)
+		require.NoError(t, err)
+		assert.Empty(t, value)
+		assert.Equal(t, 1, index)
+		assert.False(t, ok)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+
+		_, index, ok, err := Race(ctx, make(chan int), make(chan int))
+		assert.Equal(t, -1, index)
+		assert.False(t, ok)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("NoChannels", func(t *testing.T) {
+		t.Parallel()
+
+		_, index, ok, err := Race[int](context.Background())
+		assert.Equal(t, -1, index)
+		assert.False(t, ok)
+		assert.ErrorIs(t, err, ErrNoCandidates)
+	})
+}
+
+func TestRaceFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Winner", func(t *testing.T) {
+		t.Parallel()
+
+		loserCancelled := make(chan struct{})
+
+		value, index, err := RaceFunc(context.Background(),
+			func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				close(loserCancelled)
+
+				return "", ctx.Err()
+			},
+			func(ctx context.Context) (string, error) {
+				return "winner", nil
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "winner", value)
+		assert.Equal(t, 1, index)
+
+		select {
+		case <-loserCancelled:
+		case <-time.After(time.Second):
+			require.FailNow(t, "loser not cancelled")
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("failed")
+
+		_, index, err := RaceFunc(context.Background(),
+			func(ctx context.Context) (int, error) {
+				return 0, expectedErr
+			},
+		)
+		assert.Equal(t, 0, index)
+		assert.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		t.Parallel()
+
+		_, index, err := RaceFunc(context.Background(),
+			func(ctx context.Context) (int, error) {
+				panic("panic")
+			},
+		)
+		assert.Equal(t, 0, index)
+
+		var recovered *panics.ErrRecovered
+
+		require.ErrorAs(t, err, &recovered)
+		assert.Equal(t, "panic", recovered.Value)
+	})
+
+	t.Run("NoFuncs", func(t *testing.T) {
+		t.Parallel()
+
+		_, index, err := RaceFunc[int](context.Background())
+		assert.Equal(t, -1, index)
+		assert.ErrorIs(t, err, ErrNoCandidates)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, index, err := RaceFunc(ctx,
+			func(ctx context.Context) (int, error) {
+				<-release
+				return 0, nil
+			},
+		)
+		assert.Equal(t, -1, index)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -1,10 +1,18 @@
 package xo
 
+// Tee duplicates the items of src into two channels, the channels are closed once src is closed.
+//
+// Deprecated: Tee sends every item to dst1 before dst2, a slow consumer of dst1 blocks dst2.
+// Use Tee of github.com/nekomeowww/xo/exp/stream instead, which is context-aware and splits
+// into any number of channels.
 func Tee[T any](src chan T) (chan T, chan T) {
 	dst1 := make(chan T)
 	dst2 := make(chan T)
 
 	go func() {
+		defer close(dst1)
+		defer close(dst2)
+
 		for v := range src {
 			dst1 <- v
 